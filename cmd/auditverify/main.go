@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/mitcheltastic/ManproBackend/config"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	dbimpl "github.com/mitcheltastic/ManproBackend/internal/infrastructure/database"
+	"github.com/mitcheltastic/ManproBackend/internal/service"
+)
+
+// auditverify walks the audit_events hash chain and reports the first broken link.
+// It reads the same configuration as the server (DATABASE_URL from the environment or .env),
+// so it can be pointed at a local Postgres as well as production.
+//
+// Exit status: 0 if the chain is intact, 1 if a link is broken, 2 on errors.
+// The chain alone cannot reveal events cut off its end: pass -expect-id and -expect-hash with the
+// last_id and last_hash printed by an earlier run to check that checkpoint too.
+func main() {
+	os.Exit(run())
+}
+
+// run performs the verification and returns the exit status, so deferred cleanup runs before exiting.
+func run() int {
+	expectHash := flag.String("expect-hash", "", "hash of a previously verified event that must still be part of the chain")
+	expectID := flag.Int64("expect-id", 0, "ID of the event -expect-hash belongs to")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+	dbClient := dbimpl.NewClient(cfg.DatabaseURL)
+	defer dbClient.Close()
+
+	auditRepo := dbimpl.NewAuditRepository(dbClient.DB)
+	var checkpoint *domain.AuditChainCheckpoint
+	if *expectHash != "" {
+		checkpoint = &domain.AuditChainCheckpoint{ID: *expectID, Hash: *expectHash}
+	}
+
+	report, err := service.VerifyAuditChain(context.Background(), auditRepo, checkpoint)
+	if err != nil {
+		log.Printf("Audit chain verification failed: %v", err)
+		return 2
+	}
+
+	log.Printf("Checked %d chained events (%d unchained legacy events)", report.CheckedEvents, report.LegacyEvents)
+
+	if !report.Intact() {
+		log.Printf("BROKEN: first broken link at event %d: %s", report.BrokenAtID, report.Problem)
+		return 1
+	}
+
+	log.Printf("OK: chain intact, last_id=%d last_hash=%s", report.LastID, report.LastHash)
+	return 0
+}
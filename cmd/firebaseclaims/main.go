@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/mitcheltastic/ManproBackend/config"
+	dbimpl "github.com/mitcheltastic/ManproBackend/internal/infrastructure/database"
+	fbclient "github.com/mitcheltastic/ManproBackend/internal/infrastructure/firebase"
+	"github.com/mitcheltastic/ManproBackend/internal/service"
+)
+
+// firebaseclaims re-syncs the Firebase custom claims of every user linked to Firebase with their
+// local roles. Claims are pushed whenever these change, but a failed push is only logged; run this
+// after such failures, after changing roles directly in the database, or on a schedule.
+// It reads the same configuration as the server (DATABASE_URL and the FIREBASE_* settings).
+//
+// Exit status: 0 if every user was synced, 1 if some users failed, 2 on errors.
+func main() {
+	os.Exit(run())
+}
+
+// run performs the sync and returns the exit status, so deferred cleanup runs before exiting.
+func run() int {
+	ctx := context.Background()
+	cfg := config.LoadConfig()
+
+	fbAuth, err := fbclient.NewAuth(ctx, cfg)
+	if err != nil {
+		log.Printf("Error initializing Firebase: %v", err)
+		return 2
+	}
+
+	dbClient := dbimpl.NewClient(cfg.DatabaseURL)
+	defer dbClient.Close()
+
+	authRepo := dbimpl.NewAuthRepository(dbClient.DB)
+	roleRepo := dbimpl.NewRoleRepository(dbClient.DB)
+	claimsSync := service.NewFirebaseClaimsService(authRepo, roleRepo, fbAuth)
+
+	report, err := claimsSync.SyncAll(ctx)
+	if err != nil {
+		log.Printf("Firebase claims sync stopped after %d users: %v", report.Synced+report.Failed, err)
+		return 2
+	}
+
+	log.Printf("Synced the custom claims of %d users (%d failed)", report.Synced, report.Failed)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	fbclient "github.com/mitcheltastic/ManproBackend/internal/infrastructure/firebase"
+)
+
+// firebasetoken prints a test token for a server running with FIREBASE_VERIFIER=fake.
+// The token is signed with FIREBASE_FAKE_SECRET for FIREBASE_PROJECT_ID (from the environment or .env;
+// fbclient.FakeProjectID if unset) and can be used wherever a Firebase ID token is accepted,
+// e.g. POST /api/v1/auth/firebase or GET /api/v1/profile.
+func main() {
+	uid := flag.String("uid", "test-user", "Firebase UID of the test user")
+	email := flag.String("email", "test@example.com", "email address claim")
+	name := flag.String("name", "", "display name claim")
+	unverified := flag.Bool("unverified", false, "mark the email address as not verified")
+	roles := flag.String("roles", "", "comma-separated roles claim, e.g. admin,member")
+	provider := flag.String("provider", "password", "sign-in provider, e.g. password or google.com")
+	ttl := flag.Duration("ttl", time.Hour, "how long the token stays valid")
+	flag.Parse()
+
+	// A missing .env is fine: the secret can also come from the environment
+	_ = godotenv.Load()
+	secret := os.Getenv("FIREBASE_FAKE_SECRET")
+	if secret == "" {
+		log.Fatal("FIREBASE_FAKE_SECRET is not set")
+	}
+
+	claims := fbclient.FakeTokenClaims{
+		Email:         *email,
+		EmailVerified: !*unverified,
+		Name:          *name,
+	}
+	if *roles != "" {
+		claims.Roles = strings.Split(*roles, ",")
+	}
+	claims.Firebase.SignInProvider = *provider
+
+	token, err := fbclient.SignFakeToken([]byte(secret), os.Getenv("FIREBASE_PROJECT_ID"), *uid, claims, *ttl)
+	if err != nil {
+		log.Fatalf("Failed to sign token: %v", err)
+	}
+	fmt.Println(token)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -36,13 +37,16 @@ func runMigrations(db *sql.DB) {
 }
 
 func main() {
-	// 1. Load Configuration
+	// 1. Load Configuration (refuses to start with DEBUG_MODE in production)
 	cfg := config.LoadConfig()
+	log.Printf("Starting in %s environment (debug mode: %t)", cfg.AppEnv, cfg.DebugMode)
 
 	// 2. Initialize Firebase Admin SDK (Auth is already wired)
 	log.Println("Initializing Firebase Admin SDK...")
-	// *** CRITICAL FIX HERE ***: Use the path variable, not the old JSON variable
-	firebaseClient := fbclient.NewClient(cfg.FirebaseServiceKeyPath)
+	firebaseClient, err := fbclient.NewAuth(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("Error initializing Firebase: %v", err)
+	}
 	log.Println("Firebase Admin SDK initialized successfully.")
 
 	// 3. Initialize Supabase (Postgres) Database Client
@@ -61,6 +65,10 @@ func main() {
 	
 	// 5. Initialize HTTP Router
 	log.Println("Initializing HTTP router (Gin)...")
+	if !cfg.DebugMode {
+		// Release mode disables Gin's route dump and debug warnings
+		gin.SetMode(gin.ReleaseMode)
+	}
 	r := gin.Default()
 	
 	// We need to pass the database client to the router so handlers can access it
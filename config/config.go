@@ -1,20 +1,46 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"net/url"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
+	"github.com/mitcheltastic/ManproBackend/internal/pkg/security"
 )
 
+// minSecretLength is the minimum length of JWT_SECRET, CODE_HASH_SECRET and MFA_ENCRYPTION_SECRET.
+const minSecretLength = 32
+
 // Config holds all application configuration settings.
 type Config struct {
 	// Firebase service account file path, used to initialize the Admin SDK.
-	FirebaseServiceKeyPath string `envconfig:"FIREBASE_SERVICE_KEY_PATH" required:"true"`
-	
+	// Optional: without it the Application Default Credentials are used.
+	FirebaseServiceKeyPath string `envconfig:"FIREBASE_SERVICE_KEY_PATH" default:""`
+	FirebaseProjectID      string `envconfig:"FIREBASE_PROJECT_ID" default:""`
+
+	// Firebase Auth emulator address (e.g. "localhost:9099"). The Admin SDK reads the same variable.
+	FirebaseAuthEmulatorHost string `envconfig:"FIREBASE_AUTH_EMULATOR_HOST" default:""`
+
+	// FIREBASE_VERIFIER selects "firebase" (the SDK, or the emulator if configured) or "fake", which
+	// accepts HS256 test tokens signed with FIREBASE_FAKE_SECRET for FIREBASE_PROJECT_ID (see cmd/firebasetoken).
+	// Not for production.
+	FirebaseVerifier   string `envconfig:"FIREBASE_VERIFIER" default:"firebase"`
+	FirebaseFakeSecret string `envconfig:"FIREBASE_FAKE_SECRET" default:""`
+
 	// Server settings (e.g., port)
 	Port string `envconfig:"PORT" default:"8080"`
 
+	// Deployment environment: "development", "staging" or "production".
+	AppEnv string `envconfig:"APP_ENV" default:"production"`
+
+	// Enables dev-only behaviour: debug_code in reset responses, verbose auth error details
+	// and Gin debug logging. Refused at startup when APP_ENV is production.
+	DebugMode bool `envconfig:"DEBUG_MODE" default:"false"`
+
 	// Supabase/Postgres connection string
 	DatabaseURL string `envconfig:"DATABASE_URL" required:"true"`
 
@@ -22,13 +48,96 @@ type Config struct {
 	ShouldMigrate bool `envconfig:"SHOULD_MIGRATE" default:"false"`
 
 	// Secret key used to sign and verify local JWTs for custom authentication.
-	JWTSecret string `envconfig:"JWT_SECRET" required:"true"`
+	// Required when JWT_ALGORITHM is HS256.
+	JWTSecret string `envconfig:"JWT_SECRET" default:""`
+
+	// JWT signing algorithm: HS256 (shared JWT_SECRET), RS256 or EdDSA (key pair loaded from files).
+	// Asymmetric tokens carry a 'kid' header and can be verified by other services via /.well-known/jwks.json.
+	JWTAlgorithm string `envconfig:"JWT_ALGORITHM" default:"HS256"`
+
+	// PEM private key and its key ID, used to sign new tokens (RS256/EdDSA only).
+	JWTSigningKeyPath string `envconfig:"JWT_SIGNING_KEY_PATH" default:""`
+	JWTSigningKeyID   string `envconfig:"JWT_SIGNING_KEY_ID" default:""`
+
+	// Previous public keys still accepted during a key rotation, as "kid:/path/to/key.pem,kid2:/path2.pem".
+	JWTVerificationKeys map[string]string `envconfig:"JWT_VERIFICATION_KEYS" default:""`
+
+	// Secret key used to hash short codes (e.g. password reset codes) before they are stored.
+	// Falls back to JWT_SECRET if empty (not in production); set it separately so rotating one does not affect the other.
+	CodeHashSecret string `envconfig:"CODE_HASH_SECRET" default:""`
+
+	// Lifetime of the JWT access token returned by the /auth endpoints.
+	AccessTokenTTL time.Duration `envconfig:"ACCESS_TOKEN_TTL" default:"15m"`
+
+	// Lifetime of the opaque refresh token used to obtain new access tokens.
+	RefreshTokenTTL time.Duration `envconfig:"REFRESH_TOKEN_TTL" default:"720h"`
+
+	// How often expired token revocation entries and failed-attempt counters are deleted.
+	RevocationPruneInterval time.Duration `envconfig:"REVOCATION_PRUNE_INTERVAL" default:"1h"`
+
+	// Email verification: "allow" lets unverified users in, "block_login" refuses their logins,
+	// "block_protected" lets them log in but rejects them on protected routes.
+	EmailVerificationPolicy string `envconfig:"EMAIL_VERIFICATION_POLICY" default:"allow"`
+
+	// How long an email verification token stays valid.
+	EmailVerificationTTL time.Duration `envconfig:"EMAIL_VERIFICATION_TTL" default:"24h"`
+
+	// Optional frontend page that receives the token as ?token=... (added to any query it already has);
+	// if empty only the raw token is emailed.
+	EmailVerificationURL string `envconfig:"EMAIL_VERIFICATION_URL" default:""`
+
+	// Passwordless login: POST /auth/magic-link emails a single-use link that expires after MAGIC_LINK_TTL.
+	// MAGIC_LINK_URL is the frontend page that receives the token as ?token=... (added to any query it
+	// already has) and posts it to /auth/magic-link/verify; if empty only the raw token is emailed.
+	MagicLinkEnabled bool          `envconfig:"MAGIC_LINK_ENABLED" default:"false"`
+	MagicLinkTTL     time.Duration `envconfig:"MAGIC_LINK_TTL" default:"15m"`
+	MagicLinkURL     string        `envconfig:"MAGIC_LINK_URL" default:""`
+
+	// Organization invitations expire after ORG_INVITATION_TTL. ORG_INVITATION_URL is the optional
+	// frontend page that receives the token as ?token=... (added to any query it already has); if empty
+	// only the raw token is emailed.
+	OrgInvitationTTL time.Duration `envconfig:"ORG_INVITATION_TTL" default:"168h"`
+	OrgInvitationURL string        `envconfig:"ORG_INVITATION_URL" default:""`
+
+	// Brute-force protection for login and password reset. After the max number of failures
+	// the account (or IP) is locked out for LOCKOUT_BASE_DURATION, doubling on every further failure.
+	LoginMaxAttempts     int           `envconfig:"LOGIN_MAX_ATTEMPTS" default:"5"`
+	LoginIPMaxAttempts   int           `envconfig:"LOGIN_IP_MAX_ATTEMPTS" default:"20"`
+	LoginAttemptWindow   time.Duration `envconfig:"LOGIN_ATTEMPT_WINDOW" default:"15m"`
+	LockoutBaseDuration  time.Duration `envconfig:"LOCKOUT_BASE_DURATION" default:"1m"`
+	LockoutMaxDuration   time.Duration `envconfig:"LOCKOUT_MAX_DURATION" default:"1h"`
+	ResetCodeMaxAttempts int           `envconfig:"RESET_CODE_MAX_ATTEMPTS" default:"5"`
+
+	// Reverse proxies (IPs or CIDRs, comma separated) whose X-Forwarded-For header is believed when
+	// determining the client IP for the per-IP lockout and the audit log. Empty trusts no proxy, so
+	// clients cannot pick their own IP; set it to the load balancer's addresses when running behind one.
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES" default:""`
+
+	// Argon2id cost of new password hashes (memory in KiB); unset values use security.DefaultArgon2idParams.
+	// Hashes with other parameters, and legacy bcrypt hashes, are upgraded on the next successful login.
+	PasswordHashMemory      uint32 `envconfig:"PASSWORD_HASH_MEMORY"`
+	PasswordHashIterations  uint32 `envconfig:"PASSWORD_HASH_ITERATIONS"`
+	PasswordHashParallelism uint8  `envconfig:"PASSWORD_HASH_PARALLELISM"`
+
+	// Password policy for register, reset and change-password. PASSWORD_MIN_STRENGTH is a 0-4 score
+	// (see security.EstimateStrength). BREACHED_PASSWORDS_PATH is an optional local SHA-1 list
+	// (a file of hashes or a directory of 5-character prefix files, see security.OpenBreachedPasswords).
+	PasswordMinLength     int    `envconfig:"PASSWORD_MIN_LENGTH" default:"8"`
+	PasswordMaxLength     int    `envconfig:"PASSWORD_MAX_LENGTH" default:"128"`
+	PasswordMinStrength   int    `envconfig:"PASSWORD_MIN_STRENGTH" default:"2"`
+	BreachedPasswordsPath string `envconfig:"BREACHED_PASSWORDS_PATH" default:""`
+
+	// Two-factor authentication (TOTP). MFA_ENCRYPTION_SECRET encrypts TOTP secrets at rest
+	// and falls back to JWT_SECRET if empty (not in production).
+	MFAIssuer           string        `envconfig:"MFA_ISSUER" default:"ManproBackend"`
+	MFAEncryptionSecret string        `envconfig:"MFA_ENCRYPTION_SECRET" default:""`
+	MFAChallengeTTL     time.Duration `envconfig:"MFA_CHALLENGE_TTL" default:"5m"`
 
 	// --- NEW EMAIL CONFIG ---
-	SMTPHost string `envconfig:"SMTP_HOST" default:""`
-	SMTPPort string `envconfig:"SMTP_PORT" default:""`
-	SMTPUser string `envconfig:"SMTP_USER" default:""`
-	SMTPPass string `envconfig:"SMTP_PASS" default:""`
+	SMTPHost  string `envconfig:"SMTP_HOST" default:""`
+	SMTPPort  string `envconfig:"SMTP_PORT" default:""`
+	SMTPUser  string `envconfig:"SMTP_USER" default:""`
+	SMTPPass  string `envconfig:"SMTP_PASS" default:""`
 	FromEmail string `envconfig:"FROM_EMAIL" default:""`
 }
 
@@ -45,5 +154,175 @@ func LoadConfig() *Config {
 		log.Fatalf("Error loading configuration: required key missing value. %v", err)
 	}
 
+	if cfg.CodeHashSecret == "" {
+		log.Println("Note: CODE_HASH_SECRET is not set. Falling back to JWT_SECRET for hashing reset codes.")
+		cfg.CodeHashSecret = cfg.JWTSecret
+	}
+
+	if cfg.MFAEncryptionSecret == "" {
+		log.Println("Note: MFA_ENCRYPTION_SECRET is not set. Falling back to JWT_SECRET for encrypting TOTP secrets.")
+		cfg.MFAEncryptionSecret = cfg.JWTSecret
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Error loading configuration: %v", err)
+	}
+
 	return &cfg
-}
\ No newline at end of file
+}
+
+// Validate checks settings that envconfig cannot check on its own.
+// It is the startup guard that keeps dev-only features out of production.
+func (c *Config) Validate() error {
+	switch c.AppEnv {
+	case "development", "staging", "production":
+	default:
+		return fmt.Errorf("APP_ENV must be development, staging or production, got %q", c.AppEnv)
+	}
+
+	if c.IsProduction() && c.DebugMode {
+		return errors.New("DEBUG_MODE must not be enabled when APP_ENV is production")
+	}
+
+	switch c.FirebaseVerifier {
+	case "firebase":
+	case "fake":
+		if c.IsProduction() {
+			return errors.New("FIREBASE_VERIFIER=fake must not be used when APP_ENV is production")
+		}
+		if c.FirebaseFakeSecret == "" {
+			return errors.New("FIREBASE_FAKE_SECRET is required when FIREBASE_VERIFIER is fake")
+		}
+	default:
+		return fmt.Errorf("FIREBASE_VERIFIER must be firebase or fake, got %q", c.FirebaseVerifier)
+	}
+	if c.FirebaseAuthEmulatorHost != "" {
+		if c.IsProduction() {
+			return errors.New("FIREBASE_AUTH_EMULATOR_HOST must not be set when APP_ENV is production")
+		}
+		if c.FirebaseProjectID == "" {
+			return errors.New("FIREBASE_PROJECT_ID is required when FIREBASE_AUTH_EMULATOR_HOST is set")
+		}
+	}
+
+	switch c.JWTAlgorithm {
+	case "HS256":
+		if c.JWTSecret == "" {
+			return errors.New("JWT_SECRET is required when JWT_ALGORITHM is HS256")
+		}
+	case "RS256", "EdDSA":
+		if c.JWTSigningKeyPath == "" || c.JWTSigningKeyID == "" {
+			return fmt.Errorf("JWT_SIGNING_KEY_PATH and JWT_SIGNING_KEY_ID are required when JWT_ALGORITHM is %s", c.JWTAlgorithm)
+		}
+	default:
+		return fmt.Errorf("JWT_ALGORITHM must be HS256, RS256 or EdDSA, got %q", c.JWTAlgorithm)
+	}
+
+	// These fall back to JWT_SECRET, which may be empty with asymmetric signing
+	if c.CodeHashSecret == "" || c.MFAEncryptionSecret == "" {
+		return errors.New("CODE_HASH_SECRET and MFA_ENCRYPTION_SECRET must be set when JWT_SECRET is empty")
+	}
+	secrets := [][2]string{{"CODE_HASH_SECRET", c.CodeHashSecret}, {"MFA_ENCRYPTION_SECRET", c.MFAEncryptionSecret}}
+	if c.JWTAlgorithm == "HS256" {
+		secrets = append(secrets, [2]string{"JWT_SECRET", c.JWTSecret})
+	}
+	for _, secret := range secrets {
+		if len(secret[1]) < minSecretLength {
+			return fmt.Errorf("%s must be at least %d characters long", secret[0], minSecretLength)
+		}
+	}
+	// In production a leaked or rotated secret must not affect the others, so there is no fallback
+	if c.IsProduction() && (c.CodeHashSecret == c.JWTSecret || c.MFAEncryptionSecret == c.JWTSecret || c.CodeHashSecret == c.MFAEncryptionSecret) {
+		return errors.New("JWT_SECRET, CODE_HASH_SECRET and MFA_ENCRYPTION_SECRET must be set to different values when APP_ENV is production")
+	}
+
+	// time.NewTicker panics on a non-positive interval
+	if c.RevocationPruneInterval <= 0 {
+		return errors.New("REVOCATION_PRUNE_INTERVAL must be positive")
+	}
+
+	switch c.EmailVerificationPolicy {
+	case "allow", "block_login", "block_protected":
+	default:
+		return fmt.Errorf("EMAIL_VERIFICATION_POLICY must be allow, block_login or block_protected, got %q", c.EmailVerificationPolicy)
+	}
+
+	if err := c.PasswordHashParams().Validate(); err != nil {
+		return fmt.Errorf("invalid PASSWORD_HASH_* settings: %w", err)
+	}
+
+	if err := c.PasswordPolicy().Validate(); err != nil {
+		return fmt.Errorf("invalid PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH or PASSWORD_MIN_STRENGTH: %w", err)
+	}
+
+	if c.MagicLinkEnabled && c.MagicLinkTTL <= 0 {
+		return errors.New("MAGIC_LINK_TTL must be positive when MAGIC_LINK_ENABLED is set")
+	}
+	if err := validatePageURL("EMAIL_VERIFICATION_URL", c.EmailVerificationURL); err != nil {
+		return err
+	}
+	if err := validatePageURL("MAGIC_LINK_URL", c.MagicLinkURL); err != nil {
+		return err
+	}
+	if c.LoginMaxAttempts < 1 || c.LoginIPMaxAttempts < 1 || c.ResetCodeMaxAttempts < 1 {
+		return errors.New("LOGIN_MAX_ATTEMPTS, LOGIN_IP_MAX_ATTEMPTS and RESET_CODE_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.LoginAttemptWindow <= 0 || c.LockoutBaseDuration <= 0 {
+		return errors.New("LOGIN_ATTEMPT_WINDOW and LOCKOUT_BASE_DURATION must be positive")
+	}
+	if c.LockoutMaxDuration < c.LockoutBaseDuration {
+		return errors.New("LOCKOUT_MAX_DURATION must not be shorter than LOCKOUT_BASE_DURATION")
+	}
+
+	if c.OrgInvitationTTL <= 0 {
+		return errors.New("ORG_INVITATION_TTL must be positive")
+	}
+	if err := validatePageURL("ORG_INVITATION_URL", c.OrgInvitationURL); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validatePageURL checks that an optional frontend page setting is an absolute URL.
+func validatePageURL(name string, value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%s must be an absolute URL, got %q", name, value)
+	}
+	return nil
+}
+
+// IsProduction reports whether the server runs in the production environment.
+func (c *Config) IsProduction() bool {
+	return c.AppEnv == "production"
+}
+
+// PasswordHashParams returns the Argon2id cost of new password hashes. Each PASSWORD_HASH_* setting
+// left unset (0) takes its value from security.DefaultArgon2idParams.
+func (c *Config) PasswordHashParams() security.Argon2idParams {
+	params := security.DefaultArgon2idParams
+	if c.PasswordHashMemory != 0 {
+		params.Memory = c.PasswordHashMemory
+	}
+	if c.PasswordHashIterations != 0 {
+		params.Iterations = c.PasswordHashIterations
+	}
+	if c.PasswordHashParallelism != 0 {
+		params.Parallelism = c.PasswordHashParallelism
+	}
+	return params
+}
+
+// PasswordPolicy returns the password rules set by the PASSWORD_* settings. The breached-password
+// list (BREACHED_PASSWORDS_PATH) is loaded separately.
+func (c *Config) PasswordPolicy() *security.PasswordPolicy {
+	return &security.PasswordPolicy{
+		MinLength:   c.PasswordMinLength,
+		MaxLength:   c.PasswordMaxLength,
+		MinStrength: c.PasswordMinStrength,
+	}
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, so the auth middleware
+// can tell them apart from JWTs (and secret scanners can recognise leaked ones).
+const PersonalAccessTokenPrefix = "mp_pat_"
+
+// PersonalAccessToken represents a long-lived token for scripts and CI, stored in the
+// 'personal_access_tokens' table. Only the hash of the token is stored; it is shown once on creation.
+type PersonalAccessToken struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"-"`
+	Name   string    `json:"name"`
+	// TokenPrefix is the start of the token (e.g. "mp_pat_AbC123"), so users can recognise it in lists.
+	TokenPrefix string `json:"token_prefix"`
+	TokenHash   string `json:"-"`
+	// Scopes are the permissions (see Permission*) the token may use; the owner must hold them as well.
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"-"`
+}
+
+// IsActive reports whether the token can still be used.
+func (t *PersonalAccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || t.ExpiresAt.After(now))
+}
+
+// PersonalAccessTokenAuth is the result of authenticating a request with a personal access token.
+type PersonalAccessTokenAuth struct {
+	Token PersonalAccessToken
+	User  User
+	// Roles are the owner's current roles; the token's scopes further restrict what they grant.
+	Roles []string
+}
+
+// --- DTOs ---
+
+// CreatePersonalAccessTokenRequest holds the data for creating a token. ExpiresAt is optional.
+type CreatePersonalAccessTokenRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// --- Response Models ---
+
+// CreatedPersonalAccessTokenResponse is returned once on creation; Token is never shown again.
+type CreatedPersonalAccessTokenResponse struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
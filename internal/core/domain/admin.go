@@ -0,0 +1,47 @@
+package domain
+
+// Page size limits of the admin list endpoints.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// --- Request/Input Models (DTOs) ---
+
+// ListUsersRequest holds the query parameters of GET /admin/users.
+// The 'form' tags bind query string values.
+type ListUsersRequest struct {
+	// Query matches part of the name or email (case-insensitive).
+	Query    string `form:"q"`
+	Role     string `form:"role"`
+	Verified *bool  `form:"verified"`
+	Disabled *bool  `form:"disabled"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// UserFilter is the normalized form of ListUsersRequest passed to the repository.
+type UserFilter struct {
+	Query    string
+	Role     string
+	Verified *bool
+	Disabled *bool
+	Limit    int
+	Offset   int
+}
+
+// --- Response Models ---
+
+// AdminUserResponse is a user as seen by administrators, including their roles.
+type AdminUserResponse struct {
+	User
+	Roles []string `json:"roles"`
+}
+
+// UserListResponse is a page of users returned by GET /admin/users.
+type UserListResponse struct {
+	Users    []User `json:"users"`
+	Total    int    `json:"total"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
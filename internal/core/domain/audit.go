@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit event types. Names follow 'area.action'.
+const (
+	AuditEventRegister                 = "auth.register"
+	AuditEventLogin                    = "auth.login"
+	AuditEventLogout                   = "auth.logout"
+	AuditEventLogoutAll                = "auth.logout_all"
+	AuditEventMagicLinkRequested       = "auth.magic_link.requested"
+	AuditEventPasswordResetRequested   = "auth.password_reset.requested"
+	AuditEventPasswordResetCompleted   = "auth.password_reset.completed"
+	AuditEventPasswordChanged          = "account.password_changed"
+	AuditEventEmailChanged             = "account.email_changed"
+	AuditEventAccessTokenCreated       = "account.access_token_created"
+	AuditEventAccessTokenRevoked       = "account.access_token_revoked"
+	AuditEventRoleAssigned             = "role.assigned"
+	AuditEventRoleRemoved              = "role.removed"
+	AuditEventUserDisabled             = "user.disabled"
+	AuditEventUserEnabled              = "user.enabled"
+	AuditEventUserDeleted              = "user.deleted"
+	AuditEventOrgCreated               = "org.created"
+	AuditEventOrgDeleted               = "org.deleted"
+	AuditEventOrgMemberInvited         = "org.member_invited"
+	AuditEventOrgMemberJoined          = "org.member_joined"
+	AuditEventOrgMemberRoleChanged     = "org.member_role_changed"
+	AuditEventOrgMemberRemoved         = "org.member_removed"
+	AuditEventOrgInvitationRevoked     = "org.invitation_revoked"
+	AuditEventOrgInvitationDeclined    = "org.invitation_declined"
+	AuditEventProjectCreated           = "project.created"
+	AuditEventProjectArchived          = "project.archived"
+	AuditEventProjectUnarchived        = "project.unarchived"
+	AuditEventProjectDeleted           = "project.deleted"
+	AuditEventProjectMemberAdded       = "project.member_added"
+	AuditEventProjectMemberRoleChanged = "project.member_role_changed"
+	AuditEventProjectMemberRemoved     = "project.member_removed"
+)
+
+// Audit event outcomes.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEvent represents one row of the append-only 'audit_events' table.
+type AuditEvent struct {
+	ID         int64     `json:"id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Type       string    `json:"type"`
+	Outcome    string    `json:"outcome"`
+	// ActorID performed the action; uuid.Nil for anonymous requests.
+	ActorID uuid.UUID `json:"actor_id,omitzero"`
+	// SubjectID is the account the action applied to; uuid.Nil if no account matched.
+	SubjectID uuid.UUID         `json:"subject_id,omitzero"`
+	Email     string            `json:"email,omitempty"`
+	IP        string            `json:"ip"`
+	UserAgent string            `json:"user_agent"`
+	Details   map[string]string `json:"details,omitempty"`
+
+	// PrevHash is the Hash of the previous event; Hash covers this event's fields and PrevHash.
+	// Both are empty for events recorded before hash chaining was introduced.
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// ComputeHash returns the hex SHA-256 chain hash of the event. The fields are written in a fixed
+// order, each prefixed with its length, so two different events never produce the same input.
+// OccurredAt is hashed in UTC at microsecond precision, which is what Postgres stores.
+func (e *AuditEvent) ComputeHash() string {
+	var actorID, subjectID string
+	if e.ActorID != uuid.Nil {
+		actorID = e.ActorID.String()
+	}
+	if e.SubjectID != uuid.Nil {
+		subjectID = e.SubjectID.String()
+	}
+
+	details := e.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+	// Maps are marshalled with sorted keys, so the encoding is stable
+	encodedDetails, _ := json.Marshal(details)
+
+	h := sha256.New()
+	for _, field := range []string{
+		e.PrevHash,
+		e.OccurredAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		e.Type,
+		e.Outcome,
+		actorID,
+		subjectID,
+		e.Email,
+		e.IP,
+		e.UserAgent,
+		string(encodedDetails),
+	} {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+		h.Write(length[:])
+		h.Write([]byte(field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// AuditChainCheckpoint is the last_id/last_hash of an earlier verification, kept outside the database.
+// Checking it detects a log that was truncated or rebuilt from that point on.
+type AuditChainCheckpoint struct {
+	ID   int64
+	Hash string
+}
+
+// AuditChainReport is the result of walking the audit hash chain.
+type AuditChainReport struct {
+	// CheckedEvents is the number of chained events verified; LegacyEvents precede the chain unhashed.
+	CheckedEvents int
+	LegacyEvents  int
+	// LastID and LastHash identify the newest verified event. Comparing them with a previously
+	// saved value also detects rows removed from the end of the log.
+	LastID   int64
+	LastHash string
+	// BrokenAtID is the first event whose link does not verify (0 if the chain is intact).
+	BrokenAtID int64
+	Problem    string
+}
+
+// Intact reports whether every link of the chain verified.
+func (r *AuditChainReport) Intact() bool {
+	return r.BrokenAtID == 0
+}
+
+// --- Request/Input Models (DTOs) ---
+
+// ListAuditEventsRequest holds the query parameters of GET /admin/audit-events.
+// From and To are RFC 3339 timestamps; UserID matches either the actor or the subject.
+// Email matches events recorded with that address, such as failed logins for the account.
+type ListAuditEventsRequest struct {
+	UserID   string    `form:"user_id" binding:"omitempty,uuid"`
+	Email    string    `form:"email"`
+	Type     string    `form:"type"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Page     int       `form:"page" binding:"omitempty,min=1"`
+	PageSize int       `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// AuditFilter is the normalized form of ListAuditEventsRequest passed to the repository.
+type AuditFilter struct {
+	UserID uuid.UUID
+	Email  string
+	Type   string
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// --- Response Models ---
+
+// AuditEventListResponse is a page of audit events, newest first.
+type AuditEventListResponse struct {
+	Events   []AuditEvent `json:"events"`
+	Total    int          `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
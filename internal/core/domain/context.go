@@ -0,0 +1,24 @@
+package domain
+
+import "context"
+
+// ClientInfo describes the client that sent the current request.
+// It is attached to the request context by the router so services can use it
+// for rate limiting, session tracking and auditing.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo returns a copy of ctx carrying the given client information.
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext returns the client information stored in ctx, or an empty value.
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrAccountDisabled is returned when a disabled account tries to sign in or renew its session.
+var ErrAccountDisabled = errors.New("account disabled")
+
+// ErrEmailNotVerified is returned by a ports.TokenVerifier for a valid token of an unverified account
+// when the email verification policy is block_protected.
+var ErrEmailNotVerified = errors.New("email not verified")
+
+// ErrLastOrgOwner is returned when a change would leave an organization without an owner.
+var ErrLastOrgOwner = errors.New("an organization must keep at least one owner")
+
+// ErrLastProjectAdmin is returned when a change would leave a project without an admin.
+var ErrLastProjectAdmin = errors.New("a project must keep at least one admin")
+
+// TooManyAttemptsError is returned while an account or IP is temporarily locked out
+// after repeated failed attempts. RetryAfter tells the client how long to wait.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+// FieldError describes why one input field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when input passes binding but breaks a rule checked by a service
+// (e.g. the password policy). Handlers return the field errors so clients can show them per field.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
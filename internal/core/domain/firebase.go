@@ -0,0 +1,33 @@
+package domain
+
+// FirebaseRolesClaim is the custom claim that carries the user's roles in Firebase ID tokens.
+const FirebaseRolesClaim = "roles"
+
+// FirebaseIdentity holds the verified fields of a Firebase ID token.
+type FirebaseIdentity struct {
+	UID           string
+	Email         string
+	EmailVerified bool
+	Name          string
+	// SignInProvider is the Firebase provider used, e.g. "password" or "google.com".
+	SignInProvider string
+	// Roles are the roles found in the token's custom claims (set by the backend, see FirebaseClaimsUpdater).
+	Roles []string
+}
+
+// FirebaseCustomClaims builds the custom claims the backend keeps on a Firebase account.
+// The roles claim is always present, so removing the last role also clears it in Firebase.
+// Organization memberships are not published: the organization service checks them in the database.
+func FirebaseCustomClaims(roles []string) map[string]interface{} {
+	if roles == nil {
+		roles = []string{}
+	}
+	return map[string]interface{}{FirebaseRolesClaim: roles}
+}
+
+// FirebaseClaimsSyncReport is the result of re-syncing the custom claims of every Firebase-linked user.
+type FirebaseClaimsSyncReport struct {
+	Synced int
+	// Failed counts the users whose claims could not be written (each failure is logged).
+	Failed int
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTPEnrollment represents a user's authenticator app stored in the 'user_totp' table.
+type TOTPEnrollment struct {
+	UserID          uuid.UUID
+	SecretEncrypted string     // AES-GCM encrypted base32 secret
+	EnabledAt       *time.Time // nil while the enrollment still awaits confirmation
+	LastUsedStep    int64
+	CreatedAt       time.Time
+}
+
+// IsEnabled reports whether the enrollment was confirmed and is enforced at login.
+func (e *TOTPEnrollment) IsEnabled() bool {
+	return e != nil && e.EnabledAt != nil
+}
+
+// --- Request/Input Models (DTOs) ---
+
+// MFALoginRequest completes a login that returned mfa_required.
+// Exactly one of Code (from the authenticator app) or RecoveryCode must be given.
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code"`
+}
+
+// ConfirmTOTPRequest holds the first code generated by the authenticator app after enrollment.
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// DisableTOTPRequest requires a current second factor before two-factor authentication is turned off.
+type DisableTOTPRequest struct {
+	Code         string `json:"code" binding:"omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code"`
+}
+
+// --- Response Models ---
+
+// TOTPEnrollmentResponse is returned when a user starts enrolling an authenticator app.
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`      // base32 secret for manual entry
+	OTPAuthURI string `json:"otpauth_uri"` // render as a QR code
+}
+
+// RecoveryCodesResponse lists the recovery codes. They are shown only once and stored hashed.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles of a member within one organization. They are independent of the system roles (see Role*):
+// an organization owner has no extra rights outside their organization.
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// orgRoleRanks orders the organization roles from least to most privileged.
+var orgRoleRanks = map[string]int{
+	OrgRoleMember: 1,
+	OrgRoleAdmin:  2,
+	OrgRoleOwner:  3,
+}
+
+// IsOrgRole reports whether role is one of the OrgRole* values.
+func IsOrgRole(role string) bool {
+	_, ok := orgRoleRanks[role]
+	return ok
+}
+
+// OrgRoleAtLeast reports whether role grants at least the rights of minRole.
+// An empty or unknown role (not a member) grants nothing.
+func OrgRoleAtLeast(role string, minRole string) bool {
+	rank, ok := orgRoleRanks[role]
+	return ok && rank >= orgRoleRanks[minRole]
+}
+
+// Organization represents a shared workspace stored in the 'organizations' table.
+type Organization struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// CreatedBy is the user who created the organization (uuid.Nil if that account was deleted).
+	CreatedBy uuid.UUID `json:"created_by,omitzero"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrganizationMembership is an organization together with the role the user holds in it.
+type OrganizationMembership struct {
+	Organization
+	Role string `json:"role"`
+}
+
+// OrganizationMember is one row of the 'organization_members' table with the member's user details.
+type OrganizationMember struct {
+	OrgID    uuid.UUID `json:"-"`
+	UserID   uuid.UUID `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// OrganizationInvitation represents a pending invitation stored in the 'organization_invitations' table.
+// Only the hash of the token is stored; the token is emailed and lets the recipient accept or decline.
+type OrganizationInvitation struct {
+	ID        uuid.UUID `json:"id"`
+	OrgID     uuid.UUID `json:"org_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	TokenHash string    `json:"-"`
+	// InvitedBy is the member who sent the invitation (uuid.Nil if that account was deleted).
+	InvitedBy uuid.UUID `json:"invited_by,omitzero"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// --- DTOs ---
+
+// OrganizationRequest holds the data for creating or renaming an organization.
+type OrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// InviteMemberRequest holds the data for inviting someone by email. Owners are made by promoting
+// an existing member, so an invitation grants at most the admin role.
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=admin member"`
+}
+
+// UpdateMemberRoleRequest holds the new organization role of a member.
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner admin member"`
+}
+
+// InvitationTokenRequest holds the token from an invitation email (accept or decline).
+type InvitationTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Providers that can authenticate a Principal.
+const (
+	// PrincipalProviderLocal is a JWT issued by our own /auth endpoints.
+	PrincipalProviderLocal = "local"
+	// PrincipalProviderAccessToken is a personal access token (mp_pat_...).
+	PrincipalProviderAccessToken = "access_token"
+	// PrincipalProviderFirebase is a Firebase ID token.
+	PrincipalProviderFirebase = "firebase"
+)
+
+// ErrInvalidToken is returned by a ports.TokenVerifier for tokens it does not accept
+// (wrong format, bad signature, expired, revoked), so the next verifier can be tried.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Principal is the authenticated caller of a request, independent of how the token was verified.
+type Principal struct {
+	// UserID is the local user ID, or the Firebase UID of a Firebase user without a linked local account.
+	UserID        string   `json:"user_id"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Name          string   `json:"name,omitempty"`
+	Roles         []string `json:"roles"`
+	// Provider is one of the PrincipalProvider* values.
+	Provider string `json:"provider"`
+	// FirebaseUID is set for Firebase ID tokens, whether or not the account is linked.
+	FirebaseUID string `json:"firebase_uid,omitempty"`
+	// Scopes limit a personal access token to these permissions; nil means no limit beyond the roles.
+	Scopes []string `json:"scopes,omitempty"`
+	// ExpiresAt is when the token expires (nil for a personal access token without expiry).
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// SessionID and TokenID (the jti) identify a local JWT and its login, so it can be logged out.
+	// They are empty for other tokens.
+	SessionID uuid.UUID `json:"-"`
+	TokenID   string    `json:"-"`
+}
+
+// LocalUserID returns the ID of the user in our users table. It is missing only for a Firebase
+// user whose account is not linked to a local one.
+func (p *Principal) LocalUserID() (uuid.UUID, bool) {
+	if p.Provider == PrincipalProviderFirebase && p.UserID == p.FirebaseUID {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(p.UserID)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// TokenIdentity returns the identity of the current access token, for logging it out.
+func (p *Principal) TokenIdentity() TokenIdentity {
+	userID, _ := p.LocalUserID()
+	identity := TokenIdentity{
+		UserID:    userID,
+		SessionID: p.SessionID,
+		TokenID:   p.TokenID,
+	}
+	if p.ExpiresAt != nil {
+		identity.ExpiresAt = *p.ExpiresAt
+	}
+	return identity
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Project statuses. Archiving is separate from the status: an archived project keeps its status.
+const (
+	ProjectStatusPlanned   = "planned"
+	ProjectStatusActive    = "active"
+	ProjectStatusOnHold    = "on_hold"
+	ProjectStatusCompleted = "completed"
+	ProjectStatusCancelled = "cancelled"
+)
+
+// Roles of a member within one project. The system permissions (see PermissionProject*) decide
+// what a user may do with projects at all; the project role decides what they may do with this one.
+const (
+	ProjectRoleViewer = "viewer"
+	ProjectRoleEditor = "editor"
+	ProjectRoleAdmin  = "admin"
+)
+
+// projectRoleRanks orders the project roles from least to most privileged.
+var projectRoleRanks = map[string]int{
+	ProjectRoleViewer: 1,
+	ProjectRoleEditor: 2,
+	ProjectRoleAdmin:  3,
+}
+
+// ProjectRoleAtLeast reports whether role grants at least the rights of minRole.
+// An empty or unknown role (not a member) grants nothing.
+func ProjectRoleAtLeast(role string, minRole string) bool {
+	rank, ok := projectRoleRanks[role]
+	return ok && rank >= projectRoleRanks[minRole]
+}
+
+// ProjectDateLayout is the format of project start and end dates in requests.
+const ProjectDateLayout = "2006-01-02"
+
+// Project represents a project stored in the 'projects' table.
+type Project struct {
+	ID uuid.UUID `json:"id"`
+	// Key is the unique short name of the project, e.g. "MANPRO": upper-case letters and digits.
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// OwnerID is the creator (uuid.Nil if that account was deleted); the owner is always a
+	// project admin and cannot be removed.
+	OwnerID    uuid.UUID  `json:"owner_id,omitzero"`
+	StartDate  *time.Time `json:"start_date,omitempty"`
+	EndDate    *time.Time `json:"end_date,omitempty"`
+	Status     string     `json:"status"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// IsArchived reports whether the project is archived (read-only until it is unarchived).
+func (p *Project) IsArchived() bool {
+	return p.ArchivedAt != nil
+}
+
+// ProjectMembership is a project together with the role the user holds in it.
+type ProjectMembership struct {
+	Project
+	Role string `json:"role"`
+}
+
+// ProjectMember is one row of the 'project_members' table with the member's user details.
+type ProjectMember struct {
+	ProjectID uuid.UUID `json:"-"`
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	AddedAt   time.Time `json:"added_at"`
+}
+
+// ProjectFilter is the normalized form of ListProjectsRequest passed to the repository.
+// Only projects the user is a member of are listed.
+type ProjectFilter struct {
+	UserID          uuid.UUID
+	Status          string
+	IncludeArchived bool
+	Limit           int
+	Offset          int
+}
+
+// --- Request/Input Models (DTOs) ---
+
+// CreateProjectRequest holds the data for creating a project. Dates use ProjectDateLayout.
+type CreateProjectRequest struct {
+	Key         string `json:"key" binding:"required"`
+	Name        string `json:"name" binding:"required,max=200"`
+	Description string `json:"description" binding:"max=5000"`
+	StartDate   string `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate     string `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	Status      string `json:"status" binding:"omitempty,oneof=planned active on_hold completed cancelled"`
+}
+
+// UpdateProjectRequest holds the fields to change; omitted fields are kept.
+// An empty start_date or end_date clears the date. The key cannot be changed.
+type UpdateProjectRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=200"`
+	Description *string `json:"description" binding:"omitempty,max=5000"`
+	StartDate   *string `json:"start_date"`
+	EndDate     *string `json:"end_date"`
+	Status      *string `json:"status" binding:"omitempty,oneof=planned active on_hold completed cancelled"`
+}
+
+// ListProjectsRequest holds the query parameters of GET /projects.
+type ListProjectsRequest struct {
+	Status          string `form:"status" binding:"omitempty,oneof=planned active on_hold completed cancelled"`
+	IncludeArchived bool   `form:"include_archived"`
+	Page            int    `form:"page" binding:"omitempty,min=1"`
+	PageSize        int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// AddProjectMemberRequest holds the data for adding an existing user to a project.
+type AddProjectMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=viewer editor admin"`
+}
+
+// UpdateProjectMemberRequest holds the new project role of a member.
+type UpdateProjectMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor admin"`
+}
+
+// --- Response Models ---
+
+// ProjectListResponse is a page of projects returned by GET /projects.
+type ProjectListResponse struct {
+	Projects []ProjectMembership `json:"projects"`
+	Total    int                 `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+// System roles seeded by the RBAC migration.
+const (
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleMember  = "member"
+	RoleGuest   = "guest"
+)
+
+// DefaultRole is granted to every new account.
+const DefaultRole = RoleMember
+
+// Permissions checked by RequirePermission. Names follow 'resource:action'.
+const (
+	PermissionProjectRead   = "project:read"
+	PermissionProjectWrite  = "project:write"
+	PermissionProjectDelete = "project:delete"
+	PermissionUserRead      = "user:read"
+	PermissionUserWrite     = "user:write"
+	PermissionRoleAssign    = "role:assign"
+	PermissionAuditRead     = "audit:read"
+	PermissionProfileRead   = "profile:read"
+)
+
+// Role represents a role stored in the 'roles' table together with its permissions.
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	IsSystem    bool     `json:"is_system"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session represents a signed-in device stored in the 'sessions' table.
+// Its ID is also the family ID of the refresh tokens issued for that login.
+type Session struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"-"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	RevokedAt  *time.Time `json:"-"`
+}
+
+// --- Response Models ---
+
+// SessionResponse is a session as listed at GET /me/sessions.
+type SessionResponse struct {
+	Session
+	// Current marks the session the request was made with.
+	Current bool `json:"current"`
+}
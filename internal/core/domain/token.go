@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRefreshTokenReused is returned when a refresh token that was already rotated is presented again.
+// This usually means the token was stolen, so the whole token family gets revoked.
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+
+// RefreshToken represents an opaque refresh token stored in the 'refresh_tokens' table.
+// Every rotation creates a new token in the same family; FamilyID ties them together.
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	TokenHash string // SHA-256 of the token; the raw value is only ever shown to the client
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time // Set when the token is exchanged for a new pair
+	RevokedAt *time.Time
+}
+
+// TokenIdentity identifies the access token that authenticated the current request.
+// It is filled from the verified JWT claims and used to revoke that token on logout.
+type TokenIdentity struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID // The refresh token family the access token was issued with
+	TokenID   string    // The 'jti' claim
+	ExpiresAt time.Time
+}
+
+// --- Request/Input Models (DTOs) ---
+
+// RefreshTokenRequest holds the input for exchanging a refresh token for a new token pair.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
@@ -13,12 +13,29 @@ type User struct {
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
 	// HashedPassword stores the bcrypt hash. We never expose the raw password.
+	// It is empty for accounts created through Firebase sign-in.
 	HashedPassword string `json:"-"`
+	// FirebaseUID links the user to a Firebase account (empty if never signed in with Firebase).
+	FirebaseUID string `json:"firebase_uid,omitempty"`
 	IsVerified    bool   `json:"is_verified"`
+	// DisabledAt is set while an administrator has disabled the account.
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// IsDisabled reports whether the account has been disabled by an administrator.
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// Email verification policies (see config.EmailVerificationPolicy).
+const (
+	VerificationPolicyAllow          = "allow"
+	VerificationPolicyBlockLogin     = "block_login"
+	VerificationPolicyBlockProtected = "block_protected"
+)
+
 // --- Request/Input Models (DTOs) ---
 
 // RegisterRequest holds the user input for the registration endpoint.
@@ -26,7 +43,8 @@ type User struct {
 type RegisterRequest struct {
 	Name            string `json:"name" binding:"required,min=2,max=50"`
 	Email           string `json:"email" binding:"required,email"`
-	Password        string `json:"password" binding:"required,min=8"`
+	// Length and strength are checked by the password policy (see security.PasswordPolicy)
+	Password        string `json:"password" binding:"required"`
 	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=Password"`
 }
 
@@ -41,20 +59,77 @@ type ForgotPasswordRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
 
+// VerifyEmailRequest holds the token sent to the user's inbox after registration.
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerificationRequest holds the email address that should receive a new verification token.
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// MagicLinkRequest holds the email address that should receive a login link.
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// MagicLinkVerifyRequest holds the token from the emailed login link.
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// FirebaseExchangeRequest holds a Firebase ID token to be exchanged for a local AuthResponse.
+type FirebaseExchangeRequest struct {
+	IDToken string `json:"id_token" binding:"required"`
+}
+
 // ResetPasswordRequest holds the input for completing the password reset.
 type ResetPasswordRequest struct {
 	Email           string `json:"email" binding:"required,email"`
 	Code            string `json:"code" binding:"required,len=6"` // Assuming a 6-digit code
-	NewPassword     string `json:"new_password" binding:"required,min=8"`
+	NewPassword     string `json:"new_password" binding:"required"`
 	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=NewPassword"`
 }
 
+// ChangePasswordRequest holds the input for changing the password of a signed-in user.
+type ChangePasswordRequest struct {
+	CurrentPassword     string `json:"current_password" binding:"required"`
+	NewPassword         string `json:"new_password" binding:"required"`
+	ConfirmPassword     string `json:"confirm_password" binding:"required,eqfield=NewPassword"`
+	// RevokeOtherSessions signs out every other device after the change.
+	RevokeOtherSessions bool `json:"revoke_other_sessions"`
+}
+
+// ChangeEmailRequest holds the input for starting an email address change.
+type ChangeEmailRequest struct {
+	NewEmail        string `json:"new_email" binding:"required,email"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+// ConfirmEmailChangeRequest holds the code sent to the new email address.
+type ConfirmEmailChangeRequest struct {
+	Code string `json:"code" binding:"required,len=6"`
+}
+
 // --- Response Models ---
 
 // AuthResponse holds the data returned to the client upon successful registration or login.
+// When the account has two-factor authentication enabled, Login returns only MFARequired and
+// MFAToken; the tokens are issued after the second step at /auth/login/mfa.
 type AuthResponse struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Name   string    `json:"name"`
-	Token  string    `json:"token"` // The JWT issued to the client
+	UserID     uuid.UUID `json:"user_id"`
+	Email      string    `json:"email"`
+	Name       string    `json:"name"`
+	IsVerified bool      `json:"is_verified"`
+	Token      string    `json:"token,omitempty"` // The short-lived JWT access token issued to the client
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`
+	// RefreshToken is an opaque token exchanged at /auth/refresh for a new token pair.
+	RefreshToken          string    `json:"refresh_token,omitempty"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at,omitzero"`
+
+	// MFARequired is set instead of the tokens when a second factor is needed.
+	MFARequired       bool      `json:"mfa_required,omitempty"`
+	MFAToken          string    `json:"mfa_token,omitempty"`
+	MFATokenExpiresAt time.Time `json:"mfa_token_expires_at,omitzero"`
 }
\ No newline at end of file
@@ -0,0 +1,47 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// PersonalAccessTokenRepository defines the interface for storing personal access tokens.
+// The implementation will live in internal/infrastructure/database/
+type PersonalAccessTokenRepository interface {
+	// CreateToken saves a new token record.
+	CreateToken(ctx context.Context, token domain.PersonalAccessToken) error
+
+	// GetTokenByHash returns the token with the given hash, or nil if it does not exist.
+	GetTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)
+
+	// ListTokens returns the user's tokens that are not revoked (including expired ones), newest first.
+	ListTokens(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error)
+
+	// RevokeToken revokes one token of the user.
+	// Returns false if the token does not exist, belongs to another user or is already revoked.
+	RevokeToken(ctx context.Context, userID uuid.UUID, tokenID uuid.UUID) (bool, error)
+
+	// TouchToken updates last_used_at, skipping the write if it was updated within the given interval.
+	TouchToken(ctx context.Context, tokenID uuid.UUID, minInterval time.Duration) error
+}
+
+// PersonalAccessTokenService defines the interface for creating and using personal access tokens.
+// The implementation will live in internal/service/
+type PersonalAccessTokenService interface {
+	// CreateToken issues a new token; the returned response is the only time the raw token is shown.
+	CreateToken(ctx context.Context, userID uuid.UUID, req domain.CreatePersonalAccessTokenRequest) (*domain.CreatedPersonalAccessTokenResponse, error)
+
+	// ListTokens returns the user's tokens without their secret.
+	ListTokens(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error)
+
+	// RevokeToken revokes one of the user's tokens.
+	RevokeToken(ctx context.Context, userID uuid.UUID, tokenID uuid.UUID) error
+
+	// Authenticate checks a raw token from an Authorization header and records its use.
+	// Errors: "invalid personal access token", or domain.ErrAccountDisabled if the owner is disabled.
+	// See service.AccessTokenVerifier for using it in a chain of verifiers.
+	Authenticate(ctx context.Context, rawToken string) (*domain.PersonalAccessTokenAuth, error)
+}
@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// AdminService defines the interface for operators managing user accounts.
+// actorID is the administrator making the change; it is used to prevent locking oneself out.
+// The implementation will live in internal/service/
+type AdminService interface {
+	// ListUsers returns one page of users matching the filters.
+	ListUsers(ctx context.Context, req domain.ListUsersRequest) (*domain.UserListResponse, error)
+
+	// GetUser looks a user up by ID or, if the value is not a UUID, by email.
+	GetUser(ctx context.Context, idOrEmail string) (*domain.AdminUserResponse, error)
+
+	// SetUserDisabled disables (signing out every session) or re-enables an account.
+	SetUserDisabled(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, disabled bool) error
+
+	// SendPasswordReset emails the user a password reset code, as if they had used forgot-password.
+	SendPasswordReset(ctx context.Context, userID uuid.UUID) error
+
+	// MarkUserVerified marks the user's email address as verified.
+	MarkUserVerified(ctx context.Context, userID uuid.UUID) error
+
+	// DeleteUser permanently deletes the account. The user's projects pass to another project admin.
+	// The account is not deleted while the user is the last owner of an organization, or the only
+	// admin of one of their projects.
+	DeleteUser(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error
+
+	// ListRoles returns every role with its permissions.
+	ListRoles(ctx context.Context) ([]domain.Role, error)
+
+	// AssignRole grants a role to the user.
+	AssignRole(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, role string) error
+
+	// RemoveRole revokes a role from the user. Returns an error "user not found" or "role not assigned"
+	// if there was nothing to revoke.
+	RemoveRole(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, role string) error
+
+	// ListAuditEvents returns one page of audit events matching the filters.
+	ListAuditEvents(ctx context.Context, req domain.ListAuditEventsRequest) (*domain.AuditEventListResponse, error)
+}
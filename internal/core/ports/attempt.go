@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// AttemptStore keeps failed-attempt counters and lockouts for brute-force protection.
+// The implementation will live in internal/infrastructure/database/ so that all server instances share it.
+type AttemptStore interface {
+	// RecordFailure increments the failure counter of a key and returns the new count.
+	// Counters whose last failure is older than window start again from 1.
+	RecordFailure(ctx context.Context, key string, window time.Duration) (int, error)
+
+	// SetLockout rejects further attempts for the key until the given time.
+	SetLockout(ctx context.Context, key string, until time.Time) error
+
+	// GetLockedUntil returns the latest active lockout among the keys (zero time if none).
+	GetLockedUntil(ctx context.Context, keys ...string) (time.Time, error)
+
+	// ClearFailures resets the counter and lockout of a key after a successful attempt.
+	ClearFailures(ctx context.Context, key string) error
+
+	// PruneExpired deletes the counters whose last failure is older than window and whose lockout
+	// has ended, and returns how many were removed.
+	PruneExpired(ctx context.Context, window time.Duration) (int64, error)
+}
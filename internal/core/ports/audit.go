@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// AuditLogger records security-relevant events. Records can only be appended, never changed.
+// The implementation will live in internal/infrastructure/database/
+type AuditLogger interface {
+	// Record appends the event, chaining its hash to the previous event.
+	// OccurredAt is set by the logger if it is zero.
+	Record(ctx context.Context, event domain.AuditEvent) error
+}
+
+// AuditRepository reads the audit log in addition to writing it.
+type AuditRepository interface {
+	AuditLogger
+
+	// ListEvents returns one page of events matching the filter, newest first, and the total number of matches.
+	ListEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, int, error)
+
+	// ListEventsAfter returns up to limit events with an ID greater than afterID, in chain (ID) order.
+	ListEventsAfter(ctx context.Context, afterID int64, limit int) ([]domain.AuditEvent, error)
+}
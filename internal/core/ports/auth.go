@@ -2,45 +2,147 @@ package ports
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
 )
 
 // AuthRepository defines the interface for data operations related to authentication.
 // The implementation will live in internal/infrastructure/database/
 type AuthRepository interface {
-	// CreateUser saves a new user record to the database (Supabase).
+	// CreateUser saves a new user record to the database (Supabase) together with its
+	// default role (domain.DefaultRole); either both are saved or neither.
 	CreateUser(ctx context.Context, user domain.User) error
 
 	// GetUserByEmail retrieves a user by their email address.
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 
+	// GetUserByID retrieves a user by their ID.
+	GetUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+
+	// GetUserByFirebaseUID retrieves the user linked to a Firebase account.
+	GetUserByFirebaseUID(ctx context.Context, firebaseUID string) (*domain.User, error)
+
+	// LinkFirebaseUID stores the Firebase UID on a user that is not linked to another Firebase account.
+	LinkFirebaseUID(ctx context.Context, userID uuid.UUID, firebaseUID string) error
+
 	// UpdateUserPassword updates the user's password hash in the database.
 	UpdateUserPassword(ctx context.Context, userID string, newHashedPassword string) error
+
+	// ReplacePasswordHash swaps the hash only if it is still oldHash, so an upgrade on login
+	// never overwrites a password that was changed in the meantime.
+	ReplacePasswordHash(ctx context.Context, userID uuid.UUID, oldHash string, newHash string) error
 	
-	// CreatePasswordResetCode saves a unique 6-digit code linked to a user/email.
-	CreatePasswordResetCode(ctx context.Context, email string, code string) error
+	// CreatePasswordResetCode saves the keyed hash of a 6-digit code linked to a user/email.
+	CreatePasswordResetCode(ctx context.Context, email string, codeHash string) error
 	
-	// VerifyPasswordResetCode checks if the code is valid and not expired.
-	VerifyPasswordResetCode(ctx context.Context, email string, code string) error
+	// ConsumePasswordResetCode verifies the code hash (constant-time) and deletes the code atomically.
+	// After maxAttempts wrong guesses the code is deleted and a new one must be requested.
+	ConsumePasswordResetCode(ctx context.Context, email string, codeHash string, maxAttempts int) error
 	
-	// DeletePasswordResetCode removes the code after a successful reset.
+	// DeletePasswordResetCode removes any pending reset code for the email.
 	DeletePasswordResetCode(ctx context.Context, email string) error
+
+	// MarkUserVerified sets is_verified for the user after the email address was confirmed.
+	MarkUserVerified(ctx context.Context, userID uuid.UUID) error
+
+	// ReplaceEmailVerificationToken stores a new verification token hash, removing older tokens of the user.
+	ReplaceEmailVerificationToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+
+	// ConsumeEmailVerificationToken deletes a valid, unexpired token and returns its user ID.
+	// Returns uuid.Nil if the token does not exist or has expired.
+	ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (uuid.UUID, error)
+
+	// CreateEmailChangeCode saves a pending email change with the keyed hash of its confirmation code,
+	// replacing any earlier request of the user.
+	CreateEmailChangeCode(ctx context.Context, userID uuid.UUID, newEmail string, codeHash string, expiresAt time.Time) error
+
+	// ConsumeEmailChangeCode verifies the code hash (constant-time), deletes the request and returns the new email.
+	// After maxAttempts wrong guesses the request is deleted and a new one must be made.
+	ConsumeEmailChangeCode(ctx context.Context, userID uuid.UUID, codeHash string, maxAttempts int) (string, error)
+
+	// UpdateUserEmail changes the user's email address and marks it as verified.
+	// Pending password reset codes sent to the old address are removed.
+	UpdateUserEmail(ctx context.Context, userID uuid.UUID, newEmail string) error
+
+	// ReplaceMagicLinkToken stores a new magic link token hash, removing older tokens of the user.
+	ReplaceMagicLinkToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+
+	// ConsumeMagicLinkToken deletes a valid, unexpired token and returns its user ID.
+	// Returns uuid.Nil if the token does not exist or has expired.
+	ConsumeMagicLinkToken(ctx context.Context, tokenHash string) (uuid.UUID, error)
+
+	// --- Administration ---
+
+	// ListUsers returns one page of users matching the filter, newest first, and the total number of matches.
+	ListUsers(ctx context.Context, filter domain.UserFilter) ([]domain.User, int, error)
+
+	// SetUserDisabled disables or re-enables an account. Returns false if the user does not exist.
+	SetUserDisabled(ctx context.Context, userID uuid.UUID, disabled bool) (bool, error)
+
+	// DeleteUser permanently removes the user and everything that references it.
+	// Returns false if the user does not exist.
+	DeleteUser(ctx context.Context, userID uuid.UUID) (bool, error)
+
+	// ListFirebaseUsers returns up to limit users linked to Firebase with an ID after afterID, in ID order.
+	ListFirebaseUsers(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.User, error)
 }
 
 // AuthService defines the interface for core business logic related to authentication.
 // The implementation will live in internal/service/
 type AuthService interface {
 	// Register handles validation, hashing, saving the user, and issuing a token.
+	// Under the block_login verification policy no tokens are issued until the email is verified.
 	Register(ctx context.Context, req domain.RegisterRequest) (*domain.AuthResponse, error)
 
-	// Login verifies credentials and issues a token.
+	// Login verifies credentials and issues a token, or an MFA challenge if two-factor authentication is enabled.
 	Login(ctx context.Context, req domain.LoginRequest) (*domain.AuthResponse, error)
 
+	// ExchangeFirebaseToken verifies a Firebase ID token and issues local tokens for the matching user.
+	ExchangeFirebaseToken(ctx context.Context, req domain.FirebaseExchangeRequest) (*domain.AuthResponse, error)
+
+	// StartMagicLinkLogin emails a single-use login link. Returns the raw token for debug mode.
+	StartMagicLinkLogin(ctx context.Context, req domain.MagicLinkRequest) (string, error)
+
+	// VerifyMagicLink exchanges a magic link token for the tokens (or an MFA challenge).
+	VerifyMagicLink(ctx context.Context, req domain.MagicLinkVerifyRequest) (*domain.AuthResponse, error)
+
+	// CompleteMFALogin exchanges an MFA challenge plus a second factor for the tokens.
+	CompleteMFALogin(ctx context.Context, req domain.MFALoginRequest) (*domain.AuthResponse, error)
+
 	// StartPasswordReset initiates the forgot password flow (sends email/saves code).
 	// FIX: Updated return signature to include the generated code string for local debugging.
 	StartPasswordReset(ctx context.Context, req domain.ForgotPasswordRequest) (string, error)
 
-	// ResetPassword validates the code and updates the password.
+	// ResetPassword validates the code, updates the password, signs the user out on all devices and
+	// signs them in like a login (returning an MFA challenge instead of tokens when two-factor
+	// authentication is enabled).
 	ResetPassword(ctx context.Context, req domain.ResetPasswordRequest) (*domain.AuthResponse, error)
+
+	// VerifyEmail confirms the user's email address using the emailed token.
+	VerifyEmail(ctx context.Context, req domain.VerifyEmailRequest) error
+
+	// ResendVerification sends a new verification token if the account exists and is not yet verified.
+	ResendVerification(ctx context.Context, req domain.ResendVerificationRequest) error
+
+	// RefreshToken exchanges a valid refresh token for a new token pair (rotating the refresh token).
+	// Like login, it refuses disabled accounts and, under block_login, unverified ones.
+	RefreshToken(ctx context.Context, req domain.RefreshTokenRequest) (*domain.AuthResponse, error)
+
+	// Logout revokes the current access token and its session.
+	Logout(ctx context.Context, identity domain.TokenIdentity) error
+
+	// LogoutAll revokes every session and access token of the user.
+	LogoutAll(ctx context.Context, userID uuid.UUID) error
+
+	// ChangePassword replaces the password of a signed-in user after checking the current one.
+	// With RevokeOtherSessions set, every session except the current one is signed out.
+	ChangePassword(ctx context.Context, identity domain.TokenIdentity, req domain.ChangePasswordRequest) error
+
+	// StartEmailChange sends a confirmation code to the new address and a notice to the current one.
+	StartEmailChange(ctx context.Context, userID uuid.UUID, req domain.ChangeEmailRequest) error
+
+	// ConfirmEmailChange applies the pending email change once the code is confirmed.
+	ConfirmEmailChange(ctx context.Context, userID uuid.UUID, req domain.ConfirmEmailChangeRequest) error
 }
\ No newline at end of file
@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// FirebaseTokenVerifier verifies Firebase ID tokens.
+// The implementation lives in internal/infrastructure/firebase/
+type FirebaseTokenVerifier interface {
+	// VerifyIDToken checks the token signature and expiry and returns the identity it carries.
+	VerifyIDToken(ctx context.Context, idToken string) (*domain.FirebaseIdentity, error)
+}
+
+// FirebaseClaimsUpdater writes custom claims (such as domain.FirebaseRolesClaim) to a Firebase account,
+// so Firebase ID tokens carry the same authorization data as our own JWTs.
+type FirebaseClaimsUpdater interface {
+	// SetCustomClaims replaces the custom claims of the Firebase user. They appear in ID tokens issued afterwards.
+	SetCustomClaims(ctx context.Context, uid string, claims map[string]interface{}) error
+}
+
+// FirebaseClaimsSync pushes the local roles into the Firebase custom claims of linked users,
+// so clients reading the ID token see the same roles as our own JWTs carry.
+type FirebaseClaimsSync interface {
+	// SyncUser rewrites the custom claims of one user. Users not linked to Firebase are skipped.
+	SyncUser(ctx context.Context, userID uuid.UUID) error
+
+	// SyncAll rewrites the custom claims of every linked user, continuing past individual failures.
+	SyncAll(ctx context.Context) (*domain.FirebaseClaimsSyncReport, error)
+}
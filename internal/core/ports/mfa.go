@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// MFARepository defines the interface for storing TOTP enrollments and recovery codes.
+// The implementation will live in internal/infrastructure/database/
+type MFARepository interface {
+	// SavePendingTOTP stores a new, unconfirmed secret, replacing any previous pending enrollment.
+	SavePendingTOTP(ctx context.Context, userID uuid.UUID, secretEncrypted string) error
+
+	// GetTOTP retrieves the user's enrollment, or nil if there is none.
+	GetTOTP(ctx context.Context, userID uuid.UUID) (*domain.TOTPEnrollment, error)
+
+	// EnableTOTP confirms the enrollment and replaces the recovery codes in one transaction.
+	EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error
+
+	// DisableTOTP removes the enrollment and all recovery codes of the user.
+	DisableTOTP(ctx context.Context, userID uuid.UUID) error
+
+	// UseTOTPStep records a successfully used time step. Returns false if the step
+	// (or a later one) was already used, i.e. the code is being replayed.
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+
+	// ConsumeRecoveryCode marks an unused recovery code as used. Returns false if no such code exists.
+	ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+}
+
+// MFAService defines the interface for managing a user's second factor.
+// The implementation will live in internal/service/
+type MFAService interface {
+	// EnrollTOTP generates a new secret for the user; it is enforced only after ConfirmTOTP.
+	EnrollTOTP(ctx context.Context, userID uuid.UUID, email string) (*domain.TOTPEnrollmentResponse, error)
+
+	// ConfirmTOTP verifies the first code, enables two-factor authentication and returns new recovery codes.
+	ConfirmTOTP(ctx context.Context, userID uuid.UUID, req domain.ConfirmTOTPRequest) (*domain.RecoveryCodesResponse, error)
+
+	// DisableTOTP turns two-factor authentication off after checking a current code or recovery code.
+	// Wrong codes count towards the account's MFA lockout (keyed by email, like the login).
+	DisableTOTP(ctx context.Context, userID uuid.UUID, email string, req domain.DisableTOTPRequest) error
+}
@@ -0,0 +1,110 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// OrganizationRepository defines the interface for storing organizations, members and invitations.
+// The implementation will live in internal/infrastructure/database/
+type OrganizationRepository interface {
+	// CreateOrganization saves a new organization and makes ownerID its first owner, in one transaction.
+	CreateOrganization(ctx context.Context, org domain.Organization, ownerID uuid.UUID) error
+
+	// GetOrganization returns the organization, or nil if it does not exist.
+	GetOrganization(ctx context.Context, orgID uuid.UUID) (*domain.Organization, error)
+
+	// ListUserOrganizations returns the organizations the user is a member of, with their role, by name.
+	ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationMembership, error)
+
+	// RenameOrganization changes the name. Returns false if the organization does not exist.
+	RenameOrganization(ctx context.Context, orgID uuid.UUID, name string) (bool, error)
+
+	// DeleteOrganization removes the organization with its members and invitations.
+	// Returns false if the organization does not exist.
+	DeleteOrganization(ctx context.Context, orgID uuid.UUID) (bool, error)
+
+	// --- Members ---
+
+	// GetMemberRole returns the user's role in the organization, or "" if they are not a member.
+	GetMemberRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (string, error)
+
+	// ListMembers returns the members with their name and email, owners first.
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]domain.OrganizationMember, error)
+
+	// CountOwners returns the number of owners of the organization. The count is not locked, so it is
+	// only a hint: UpdateMemberRole and RemoveMember check for the last owner themselves.
+	CountOwners(ctx context.Context, orgID uuid.UUID) (int, error)
+
+	// UpdateMemberRole changes a member's role. Returns false if the user is not a member, and
+	// domain.ErrLastOrgOwner if they are the only owner and the role is not owner.
+	UpdateMemberRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, role string) (bool, error)
+
+	// RemoveMember removes the user from the organization. Returns false if they are not a member,
+	// and domain.ErrLastOrgOwner if they are the only owner.
+	RemoveMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (bool, error)
+
+	// --- Invitations ---
+
+	// SaveInvitation stores an invitation, replacing a pending one for the same organization and email.
+	SaveInvitation(ctx context.Context, invitation domain.OrganizationInvitation) error
+
+	// GetInvitationByTokenHash returns the invitation with the given token hash, or nil if it does not exist.
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*domain.OrganizationInvitation, error)
+
+	// ListInvitations returns the organization's pending invitations (including expired ones), newest first.
+	ListInvitations(ctx context.Context, orgID uuid.UUID) ([]domain.OrganizationInvitation, error)
+
+	// DeleteInvitation removes one invitation of the organization. Returns false if it does not exist.
+	DeleteInvitation(ctx context.Context, orgID uuid.UUID, invitationID uuid.UUID) (bool, error)
+
+	// AcceptInvitation deletes the invitation and adds the user as a member with its role, in one
+	// transaction. Returns false if the invitation no longer exists (it was used, declined or revoked).
+	AcceptInvitation(ctx context.Context, invitation domain.OrganizationInvitation, userID uuid.UUID) (bool, error)
+}
+
+// OrganizationService defines the interface for managing organizations and their members.
+// Every method checks the caller's (actorID's) role in the organization.
+// The implementation will live in internal/service/
+type OrganizationService interface {
+	// CreateOrganization creates an organization owned by the caller.
+	CreateOrganization(ctx context.Context, actorID uuid.UUID, req domain.OrganizationRequest) (*domain.OrganizationMembership, error)
+
+	// ListOrganizations returns the caller's organizations with their role.
+	ListOrganizations(ctx context.Context, actorID uuid.UUID) ([]domain.OrganizationMembership, error)
+
+	// GetOrganization returns an organization the caller is a member of.
+	GetOrganization(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID) (*domain.OrganizationMembership, error)
+
+	// RenameOrganization changes the name (admins and owners).
+	RenameOrganization(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, req domain.OrganizationRequest) (*domain.OrganizationMembership, error)
+
+	// DeleteOrganization deletes the organization (owners only).
+	DeleteOrganization(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID) error
+
+	// ListMembers returns the members of an organization the caller belongs to.
+	ListMembers(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID) ([]domain.OrganizationMember, error)
+
+	// UpdateMemberRole changes a member's role (admins; only owners can grant or change the owner role).
+	UpdateMemberRole(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, userID uuid.UUID, req domain.UpdateMemberRoleRequest) error
+
+	// RemoveMember removes a member (admins; only owners can remove owners). Members can always leave.
+	RemoveMember(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, userID uuid.UUID) error
+
+	// InviteMember emails an invitation (admins and owners). The raw token is returned for testing.
+	InviteMember(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, req domain.InviteMemberRequest) (*domain.OrganizationInvitation, string, error)
+
+	// ListInvitations returns the pending invitations (admins and owners).
+	ListInvitations(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID) ([]domain.OrganizationInvitation, error)
+
+	// RevokeInvitation deletes a pending invitation (admins and owners).
+	RevokeInvitation(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, invitationID uuid.UUID) error
+
+	// AcceptInvitation adds the caller to the organization; the invitation must be for their verified email.
+	AcceptInvitation(ctx context.Context, actorID uuid.UUID, req domain.InvitationTokenRequest) (*domain.OrganizationMembership, error)
+
+	// DeclineInvitation deletes the invitation. The token alone is enough, so no account is needed.
+	DeclineInvitation(ctx context.Context, req domain.InvitationTokenRequest) error
+}
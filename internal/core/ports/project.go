@@ -0,0 +1,97 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// ProjectRepository defines the interface for storing projects and their members.
+// The implementation will live in internal/infrastructure/database/
+type ProjectRepository interface {
+	// CreateProject saves a new project and makes its owner a project admin, in one transaction.
+	// Returns an error "project key already in use" if the key is taken.
+	CreateProject(ctx context.Context, project domain.Project) error
+
+	// GetProject returns the project, or nil if it does not exist.
+	GetProject(ctx context.Context, projectID uuid.UUID) (*domain.Project, error)
+
+	// GetProjectByKey returns the project with the given key, or nil if it does not exist.
+	GetProjectByKey(ctx context.Context, key string) (*domain.Project, error)
+
+	// ListProjects returns one page of the filter user's projects with their role, by key,
+	// and the total number of matches.
+	ListProjects(ctx context.Context, filter domain.ProjectFilter) ([]domain.ProjectMembership, int, error)
+
+	// UpdateProject saves the name, description, dates and status. Returns false if the project does not exist.
+	UpdateProject(ctx context.Context, project domain.Project) (bool, error)
+
+	// SetProjectArchived archives or unarchives the project. Returns false if the project does not exist.
+	SetProjectArchived(ctx context.Context, projectID uuid.UUID, archived bool) (bool, error)
+
+	// DeleteProject removes the project with its members. Returns false if the project does not exist.
+	DeleteProject(ctx context.Context, projectID uuid.UUID) (bool, error)
+
+	// TransferOwnedProjects makes another admin (the longest-standing one) the owner of every project
+	// the user owns, so the projects survive the deletion of the user's account. If any of those projects
+	// has no other admin, nothing is changed and their keys are returned.
+	TransferOwnedProjects(ctx context.Context, userID uuid.UUID) ([]string, error)
+
+	// --- Members ---
+
+	// GetMemberRole returns the user's role in the project, or "" if they are not a member.
+	GetMemberRole(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (string, error)
+
+	// ListMembers returns the members with their name and email, admins first.
+	ListMembers(ctx context.Context, projectID uuid.UUID) ([]domain.ProjectMember, error)
+
+	// AddMember adds the user with the role. Returns false if they already are a member.
+	AddMember(ctx context.Context, projectID uuid.UUID, userID uuid.UUID, role string) (bool, error)
+
+	// UpdateMemberRole changes a member's role. Returns false if the user is not a member, and
+	// domain.ErrLastProjectAdmin if they are the only admin and the role is not admin.
+	UpdateMemberRole(ctx context.Context, projectID uuid.UUID, userID uuid.UUID, role string) (bool, error)
+
+	// RemoveMember removes the user from the project. Returns false if they are not a member,
+	// and domain.ErrLastProjectAdmin if they are the only admin.
+	RemoveMember(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (bool, error)
+}
+
+// ProjectService defines the interface for managing projects and their members.
+// Projects are identified by their ID or their key; every method checks the caller's (actorID's)
+// role in the project: viewers read, editors edit, admins manage members, archive and delete.
+// The implementation will live in internal/service/
+type ProjectService interface {
+	// CreateProject creates a project owned by the caller.
+	CreateProject(ctx context.Context, actorID uuid.UUID, req domain.CreateProjectRequest) (*domain.ProjectMembership, error)
+
+	// ListProjects returns one page of the caller's projects.
+	ListProjects(ctx context.Context, actorID uuid.UUID, req domain.ListProjectsRequest) (*domain.ProjectListResponse, error)
+
+	// GetProject returns a project the caller is a member of.
+	GetProject(ctx context.Context, actorID uuid.UUID, idOrKey string) (*domain.ProjectMembership, error)
+
+	// UpdateProject changes the given fields (editors and admins; not while archived).
+	UpdateProject(ctx context.Context, actorID uuid.UUID, idOrKey string, req domain.UpdateProjectRequest) (*domain.ProjectMembership, error)
+
+	// SetProjectArchived archives or unarchives the project (admins).
+	SetProjectArchived(ctx context.Context, actorID uuid.UUID, idOrKey string, archived bool) (*domain.ProjectMembership, error)
+
+	// DeleteProject permanently deletes the project (admins).
+	DeleteProject(ctx context.Context, actorID uuid.UUID, idOrKey string) error
+
+	// ListMembers returns the members of a project the caller belongs to.
+	ListMembers(ctx context.Context, actorID uuid.UUID, idOrKey string) ([]domain.ProjectMember, error)
+
+	// AddMember adds an existing user by email (admins; not while archived).
+	AddMember(ctx context.Context, actorID uuid.UUID, idOrKey string, req domain.AddProjectMemberRequest) (*domain.ProjectMember, error)
+
+	// UpdateMemberRole changes a member's role (admins; not while archived). The owner stays an admin,
+	// and the last admin cannot be demoted.
+	UpdateMemberRole(ctx context.Context, actorID uuid.UUID, idOrKey string, userID uuid.UUID, req domain.UpdateProjectMemberRequest) error
+
+	// RemoveMember removes a member (admins; not while archived). Members can leave themselves, also from
+	// an archived project; the owner and the last admin cannot.
+	RemoveMember(ctx context.Context, actorID uuid.UUID, idOrKey string, userID uuid.UUID) error
+}
@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// RoleRepository defines the interface for roles, their permissions and role assignments.
+// The implementation will live in internal/infrastructure/database/
+type RoleRepository interface {
+	// ListRoles returns every role with its permissions.
+	ListRoles(ctx context.Context) ([]domain.Role, error)
+
+	// GetUserRoles returns the names of the roles granted to the user (empty if none).
+	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+
+	// AssignRole grants a role to the user. Granting a role the user already has is not an error.
+	// Returns an error "role does not exist" or "user not found" for unknown names or IDs.
+	AssignRole(ctx context.Context, userID uuid.UUID, role string) error
+
+	// RemoveRole revokes a role from the user. Returns false if the user does not have the role.
+	RemoveRole(ctx context.Context, userID uuid.UUID, role string) (bool, error)
+
+	// HasPermission reports whether any of the roles grants the permission.
+	HasPermission(ctx context.Context, roles []string, permission string) (bool, error)
+}
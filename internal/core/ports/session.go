@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// SessionRepository defines the interface for storing signed-in sessions.
+// The implementation will live in internal/infrastructure/database/
+type SessionRepository interface {
+	// CreateSession saves a new session record.
+	CreateSession(ctx context.Context, session domain.Session) error
+
+	// ListActiveSessions returns the user's sessions that are not revoked, most recently used first.
+	ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error)
+
+	// RevokeSession revokes one session of the user and its refresh tokens.
+	// Returns false if the session does not exist, belongs to another user or is already revoked.
+	RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (bool, error)
+
+	// RevokeAllSessions revokes every session of the user except keepSessionID (uuid.Nil keeps none).
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID, keepSessionID uuid.UUID) error
+
+	// TouchSession updates last_seen_at, skipping the write if it was updated within the given interval.
+	TouchSession(ctx context.Context, sessionID uuid.UUID, minInterval time.Duration) error
+}
+
+// SessionService defines the interface for users managing their own sessions.
+// The implementation will live in internal/service/
+type SessionService interface {
+	// ListSessions returns the user's active sessions, marking the current one.
+	ListSessions(ctx context.Context, userID uuid.UUID, currentSessionID uuid.UUID) ([]domain.SessionResponse, error)
+
+	// RevokeSession signs the user out of one of their sessions.
+	RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error
+}
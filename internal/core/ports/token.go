@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// TokenRepository defines the interface for storing refresh tokens.
+// The implementation will live in internal/infrastructure/database/
+type TokenRepository interface {
+	// CreateRefreshToken saves a new refresh token (only its hash is stored).
+	CreateRefreshToken(ctx context.Context, token domain.RefreshToken) error
+
+	// GetRefreshTokenByHash retrieves a refresh token by its hash, or nil if it does not exist.
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+
+	// RotateRefreshToken marks the old token as used and saves its replacement in one transaction.
+	// Returns domain.ErrRefreshTokenReused if the old token was already used or revoked.
+	RotateRefreshToken(ctx context.Context, oldTokenID uuid.UUID, next domain.RefreshToken) error
+
+	// RevokeRefreshTokenFamily revokes every token that descends from the same login.
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+
+	// RevokeAllRefreshTokens revokes every active refresh token belonging to a user.
+	RevokeAllRefreshTokens(ctx context.Context, userID uuid.UUID) error
+}
+
+// RevocationStore keeps track of access tokens that were revoked before they expired.
+// Entries only need to live until the revoked tokens would have expired anyway.
+type RevocationStore interface {
+	// RevokeToken revokes a single access token by its 'jti' claim.
+	RevokeToken(ctx context.Context, tokenID string, userID uuid.UUID, expiresAt time.Time) error
+
+	// ConsumeToken marks a single-use token (e.g. an MFA challenge) as used by its 'jti' claim.
+	// Returns false if the token was already used or revoked.
+	ConsumeToken(ctx context.Context, tokenID string, userID uuid.UUID, expiresAt time.Time) (bool, error)
+
+	// RevokeAllForUser revokes every access token of a user issued before the given time. The iat claim
+	// only has whole seconds, so tokens issued later within the same second are revoked too.
+	// expiresAt is when the last of those tokens expires, after which the entry can be pruned.
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, expiresAt time.Time) error
+
+	// IsRevoked reports whether the token was revoked individually, by a user-wide revocation,
+	// or through its session (sessionID may be uuid.Nil for tokens without a session).
+	// Tokens of disabled or deleted accounts are reported as revoked too.
+	IsRevoked(ctx context.Context, tokenID string, userID uuid.UUID, sessionID uuid.UUID, issuedAt time.Time) (bool, error)
+
+	// PruneExpired deletes entries whose tokens have expired and returns how many were removed.
+	PruneExpired(ctx context.Context) (int64, error)
+}
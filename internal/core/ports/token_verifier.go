@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// TokenVerifier verifies a bearer token of one provider (local JWT, personal access token, Firebase).
+// Several verifiers can be chained: each one either accepts the token or returns an error
+// wrapping domain.ErrInvalidToken so the next one is tried.
+type TokenVerifier interface {
+	// VerifyToken returns the caller the token belongs to. Errors that do not wrap
+	// domain.ErrInvalidToken mean the token could not be checked (e.g. the database is down).
+	VerifyToken(ctx context.Context, token string) (*domain.Principal, error)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// AccessTokenHandler handles HTTP requests for the current user's personal access tokens.
+type AccessTokenHandler struct {
+	TokenService ports.PersonalAccessTokenService
+}
+
+// NewAccessTokenHandler creates a new instance of the AccessTokenHandler.
+func NewAccessTokenHandler(tokenService ports.PersonalAccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{
+		TokenService: tokenService,
+	}
+}
+
+// ListTokens returns the user's personal access tokens without their secret (GET /api/v1/me/tokens)
+func (h *AccessTokenHandler) ListTokens(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.TokenService.ListTokens(c, userID)
+	if err != nil {
+		log.Printf("Access token list error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list access tokens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// CreateToken issues a personal access token; the token is only shown in this response (POST /api/v1/me/tokens)
+func (h *AccessTokenHandler) CreateToken(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	token, err := h.TokenService.CreateToken(c, userID, req)
+	if err != nil {
+		if err.Error() == "expiry must be in the future" || strings.HasPrefix(err.Error(), "scope not permitted") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Access token creation failed", "details": err.Error()})
+			return
+		}
+		log.Printf("Access token creation error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Access token creation failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// RevokeToken revokes one of the user's personal access tokens (DELETE /api/v1/me/tokens/:id)
+func (h *AccessTokenHandler) RevokeToken(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	tokenID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid access token ID"})
+		return
+	}
+
+	if err := h.TokenService.RevokeToken(c, userID, tokenID); err != nil {
+		if err.Error() == "access token not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Access token not found"})
+			return
+		}
+		log.Printf("Access token revoke error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke access token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Access token revoked"})
+}
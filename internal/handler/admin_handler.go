@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// AdminHandler handles HTTP requests of the admin-only user management API.
+// Access is restricted by the RequirePermission middleware on the routes.
+type AdminHandler struct {
+	AdminService ports.AdminService
+}
+
+// NewAdminHandler creates a new instance of the AdminHandler.
+func NewAdminHandler(adminService ports.AdminService) *AdminHandler {
+	return &AdminHandler{
+		AdminService: adminService,
+	}
+}
+
+// ListUsers returns a filtered page of users (GET /api/v1/admin/users)
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	var req domain.ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	users, err := h.AdminService.ListUsers(c, req)
+	if err != nil {
+		log.Printf("Admin user list error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// GetUser returns one user by ID or email (GET /api/v1/admin/users/:id)
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, err := h.AdminService.GetUser(c, c.Param("id"))
+	if err != nil {
+		respondAdminError(c, "Failed to get user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DisableUser disables an account and signs it out everywhere (POST /api/v1/admin/users/:id/disable)
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	h.setUserDisabled(c, true)
+}
+
+// EnableUser re-enables a disabled account (POST /api/v1/admin/users/:id/enable)
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	h.setUserDisabled(c, false)
+}
+
+func (h *AdminHandler) setUserDisabled(c *gin.Context, disabled bool) {
+	actorID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminService.SetUserDisabled(c, actorID, userID, disabled); err != nil {
+		respondAdminError(c, "Failed to update account status", err)
+		return
+	}
+
+	message := "User enabled"
+	if disabled {
+		message = "User disabled"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// SendPasswordReset emails the user a password reset code (POST /api/v1/admin/users/:id/password-reset)
+func (h *AdminHandler) SendPasswordReset(c *gin.Context) {
+	_, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminService.SendPasswordReset(c, userID); err != nil {
+		respondAdminError(c, "Failed to send password reset", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset code sent"})
+}
+
+// MarkUserVerified marks the user's email address as verified (POST /api/v1/admin/users/:id/verify)
+func (h *AdminHandler) MarkUserVerified(c *gin.Context) {
+	_, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminService.MarkUserVerified(c, userID); err != nil {
+		respondAdminError(c, "Failed to verify user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User marked as verified"})
+}
+
+// DeleteUser permanently deletes an account (DELETE /api/v1/admin/users/:id)
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	actorID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminService.DeleteUser(c, actorID, userID); err != nil {
+		respondAdminError(c, "Failed to delete user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted"})
+}
+
+// ListRoles returns every role with its permissions (GET /api/v1/admin/roles)
+func (h *AdminHandler) ListRoles(c *gin.Context) {
+	roles, err := h.AdminService.ListRoles(c)
+	if err != nil {
+		log.Printf("Admin role list error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list roles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// AssignRole grants a role to the user (PUT /api/v1/admin/users/:id/roles/:role)
+func (h *AdminHandler) AssignRole(c *gin.Context) {
+	actorID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminService.AssignRole(c, actorID, userID, c.Param("role")); err != nil {
+		respondAdminError(c, "Failed to assign role", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role assigned"})
+}
+
+// RemoveRole revokes a role from the user (DELETE /api/v1/admin/users/:id/roles/:role)
+func (h *AdminHandler) RemoveRole(c *gin.Context) {
+	actorID, userID, ok := adminTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.AdminService.RemoveRole(c, actorID, userID, c.Param("role")); err != nil {
+		respondAdminError(c, "Failed to remove role", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role removed"})
+}
+
+// adminTarget returns the ID of the calling administrator and the user ID from the path.
+// It writes an error response and returns false if either is missing or invalid.
+func adminTarget(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return actorID, userID, true
+}
+
+// respondAdminError maps the errors of the AdminService to HTTP responses.
+func respondAdminError(c *gin.Context, message string, err error) {
+	switch err.Error() {
+	case "user not found", "role not assigned":
+		c.JSON(http.StatusNotFound, gin.H{"error": message, "details": err.Error()})
+	case "cannot disable your own account", "cannot delete your own account", "cannot remove your own admin role", "role does not exist":
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "details": err.Error()})
+	case "user is the only owner of an organization", "user is the only admin of a project":
+		c.JSON(http.StatusConflict, gin.H{"error": message, "details": err.Error()})
+	default:
+		log.Printf("Admin error (%s): %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// ListAuditEvents returns a filtered page of the audit log (GET /api/v1/admin/audit-events)
+func (h *AdminHandler) ListAuditEvents(c *gin.Context) {
+	var req domain.ListAuditEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	events, err := h.AdminService.ListAuditEvents(c, req)
+	if err != nil {
+		if err.Error() == "invalid user ID" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+			return
+		}
+		log.Printf("Audit event list error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list audit events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
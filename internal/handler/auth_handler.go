@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
@@ -12,12 +15,15 @@ import (
 // AuthHandler handles HTTP requests related to standard authentication (Register, Login).
 type AuthHandler struct {
 	AuthService ports.AuthService
+	// DebugMode enables dev-only response fields such as debug_code (see config.DebugMode).
+	DebugMode bool
 }
 
 // NewAuthHandler creates a new instance of the AuthHandler.
-func NewAuthHandler(authService ports.AuthService) *AuthHandler {
+func NewAuthHandler(authService ports.AuthService, debugMode bool) *AuthHandler {
 	return &AuthHandler{
 		AuthService: authService,
+		DebugMode:   debugMode,
 	}
 }
 
@@ -41,6 +47,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	// Call the service layer business logic
 	authResponse, err := h.AuthService.Register(c, req)
 	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
 		log.Printf("Registration error: %v", err)
 		// Check for specific errors (e.g., duplicate email)
 		if err.Error() == "a user with this email already exists" {
@@ -65,11 +74,22 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	authResponse, err := h.AuthService.Login(c, req)
 	if err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
 		// Use generic message for security if credentials fail
 		if err.Error() == "invalid credentials" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 			return
 		}
+		if err.Error() == "email not verified" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email address before logging in"})
+			return
+		}
+		if errors.Is(err, domain.ErrAccountDisabled) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This account has been disabled"})
+			return
+		}
 		log.Printf("Login error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
 		return
@@ -78,6 +98,71 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, authResponse)
 }
 
+// FirebaseExchange signs in with a Firebase ID token (POST /api/v1/auth/firebase)
+func (h *AuthHandler) FirebaseExchange(c *gin.Context) {
+	var req domain.FirebaseExchangeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	authResponse, err := h.AuthService.ExchangeFirebaseToken(c, req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid firebase token":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired Firebase token"})
+		case "firebase email is not verified", "user is already linked to another Firebase account":
+			c.JSON(http.StatusConflict, gin.H{"error": "Firebase sign-in failed", "details": err.Error()})
+		case "firebase account has no email address":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Firebase sign-in failed", "details": err.Error()})
+		case "email not verified":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email address before logging in"})
+		case domain.ErrAccountDisabled.Error():
+			c.JSON(http.StatusForbidden, gin.H{"error": "This account has been disabled"})
+		case "firebase sign-in is not configured":
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "Firebase sign-in is not available"})
+		default:
+			log.Printf("Firebase exchange error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Firebase sign-in failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, authResponse)
+}
+
+// LoginMFA completes a login that returned mfa_required (POST /api/v1/auth/login/mfa)
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req domain.MFALoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	authResponse, err := h.AuthService.CompleteMFALogin(c, req)
+	if err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid or expired MFA token":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token. Please log in again."})
+		case "invalid two-factor code":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+		case domain.ErrAccountDisabled.Error():
+			c.JSON(http.StatusForbidden, gin.H{"error": "This account has been disabled"})
+		default:
+			log.Printf("MFA login error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, authResponse)
+}
+
 // StartPasswordReset handles initiating the forgot password flow (POST /api/v1/auth/forgot-password)
 // REVISED: Now correctly captures the 'code' and 'err' return values.
 func (h *AuthHandler) StartPasswordReset(c *gin.Context) {
@@ -97,9 +182,9 @@ func (h *AuthHandler) StartPasswordReset(c *gin.Context) {
 	}
 	
 	// IMPORTANT: Always return success (200 OK) even if the user is not found (code will be empty).
-	// We return the code only for local testing.
+	// The code is only echoed back in debug mode, which can never be enabled in production.
 	response := gin.H{"message": "If the email is registered, a password reset code has been sent."}
-	if code != "" {
+	if h.DebugMode && code != "" {
 		response["debug_code"] = code // Include code for testing purposes
 	}
 
@@ -117,6 +202,18 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 
 	authResponse, err := h.AuthService.ResetPassword(c, req)
 	if err != nil {
+		if respondTooManyAttempts(c, err) || respondValidationError(c, err) {
+			return
+		}
+		// The password was changed, but the sign-in that follows it is refused like a login
+		if err.Error() == "email not verified" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Password updated. Please verify your email address before logging in"})
+			return
+		}
+		if errors.Is(err, domain.ErrAccountDisabled) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This account has been disabled"})
+			return
+		}
 		// Return specific error messages for user feedback on reset failure
 		log.Printf("Password reset error: %v", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Password reset failed", "details": err.Error()})
@@ -124,4 +221,289 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, authResponse)
-}
\ No newline at end of file
+}
+
+// StartMagicLinkLogin emails a passwordless login link (POST /api/v1/auth/magic-link)
+func (h *AuthHandler) StartMagicLinkLogin(c *gin.Context) {
+	var req domain.MagicLinkRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	token, err := h.AuthService.StartMagicLinkLogin(c, req)
+	if err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
+		if err.Error() == "magic link login is not enabled" {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "Magic link login is not available"})
+			return
+		}
+		log.Printf("Magic link initiation error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send magic link"})
+		return
+	}
+
+	// Same response whether or not the email is registered; the token is only echoed back in debug mode
+	response := gin.H{"message": "If the email is registered, a login link has been sent."}
+	if h.DebugMode && token != "" {
+		response["debug_token"] = token
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// VerifyMagicLink signs in with the token from a magic link (POST /api/v1/auth/magic-link/verify)
+func (h *AuthHandler) VerifyMagicLink(c *gin.Context) {
+	var req domain.MagicLinkVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	authResponse, err := h.AuthService.VerifyMagicLink(c, req)
+	if err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid or expired magic link":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired login link"})
+		case "magic link login is not enabled":
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "Magic link login is not available"})
+		case domain.ErrAccountDisabled.Error():
+			c.JSON(http.StatusForbidden, gin.H{"error": "This account has been disabled"})
+		default:
+			log.Printf("Magic link login error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, authResponse)
+}
+
+// VerifyEmail confirms the user's email address (POST /api/v1/auth/verify-email)
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.AuthService.VerifyEmail(c, req); err != nil {
+		if err.Error() == "invalid or expired verification token" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email verification failed", "details": err.Error()})
+			return
+		}
+		log.Printf("Email verification error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Email verification failed"})
+		return
+	}
+
+	// Existing access tokens still carry email_verified=false until they are refreshed
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified successfully. Refresh your token to update your session."})
+}
+
+// ResendVerification sends a new verification email (POST /api/v1/auth/resend-verification)
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req domain.ResendVerificationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.AuthService.ResendVerification(c, req); err != nil {
+		log.Printf("Verification resend error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resend verification email"})
+		return
+	}
+
+	// Always return the same message so the endpoint cannot be used to probe for accounts
+	c.JSON(http.StatusOK, gin.H{"message": "If the email is registered and not yet verified, a verification email has been sent."})
+}
+
+// RefreshToken exchanges a refresh token for a new token pair (POST /api/v1/auth/refresh)
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req domain.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	authResponse, err := h.AuthService.RefreshToken(c, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRefreshTokenReused):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has already been used. Please log in again."})
+		case err.Error() == "invalid refresh token":
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		case errors.Is(err, domain.ErrAccountDisabled):
+			c.JSON(http.StatusForbidden, gin.H{"error": "This account has been disabled"})
+		case err.Error() == "email not verified":
+			c.JSON(http.StatusForbidden, gin.H{"error": "Please verify your email address before logging in"})
+		default:
+			log.Printf("Token refresh error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Token refresh failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, authResponse)
+}
+
+// Logout revokes the current access token and its session (POST /api/v1/auth/logout)
+func (h *AuthHandler) Logout(c *gin.Context) {
+	principal, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.AuthService.Logout(c, principal.TokenIdentity()); err != nil {
+		log.Printf("Logout error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Logout failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully."})
+}
+
+// LogoutAll revokes every session of the current user (POST /api/v1/auth/logout-all)
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.AuthService.LogoutAll(c, userID); err != nil {
+		log.Printf("Logout-all error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Logout failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out from all sessions."})
+}
+
+// respondTooManyAttempts writes a 429 response with a Retry-After header if err is a lockout.
+// It returns false (and writes nothing) for any other error.
+func respondTooManyAttempts(c *gin.Context, err error) bool {
+	var lockoutErr *domain.TooManyAttemptsError
+	if !errors.As(err, &lockoutErr) {
+		return false
+	}
+
+	// Retry-After is expressed in whole seconds, rounded up
+	retryAfter := int(math.Ceil(lockoutErr.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed attempts. Please try again later.", "retry_after_seconds": retryAfter})
+	return true
+}
+
+// respondValidationError writes a 400 response listing the rejected fields if err is a
+// *domain.ValidationError (e.g. a password that breaks the policy), and reports whether it did.
+func respondValidationError(c *gin.Context, err error) bool {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "fields": validationErr.Fields})
+	return true
+}
+
+// ChangePassword changes the password of the current user (POST /api/v1/me/password)
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	principal, _, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.AuthService.ChangePassword(c, principal.TokenIdentity(), req); err != nil {
+		if respondTooManyAttempts(c, err) || respondValidationError(c, err) {
+			return
+		}
+		if err.Error() == "current password is incorrect" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Password change failed", "details": err.Error()})
+			return
+		}
+		log.Printf("Password change error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Password change failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully."})
+}
+
+// StartEmailChange sends a confirmation code to the new address (POST /api/v1/me/email)
+func (h *AuthHandler) StartEmailChange(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.AuthService.StartEmailChange(c, userID, req); err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "current password is incorrect", "new email is the same as the current email":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email change failed", "details": err.Error()})
+		case "a user with this email already exists":
+			c.JSON(http.StatusConflict, gin.H{"error": "Email change failed", "details": err.Error()})
+		default:
+			log.Printf("Email change error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Email change failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "A confirmation code has been sent to the new email address."})
+}
+
+// ConfirmEmailChange applies the pending email change (POST /api/v1/me/email/confirm)
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.AuthService.ConfirmEmailChange(c, userID, req); err != nil {
+		switch err.Error() {
+		case "no pending email change", "verification code expired", "invalid verification code", "too many invalid attempts, please request a new code":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email change failed", "details": err.Error()})
+		case "a user with this email already exists":
+			c.JSON(http.StatusConflict, gin.H{"error": "Email change failed", "details": err.Error()})
+		default:
+			log.Printf("Email change confirmation error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Email change failed"})
+		}
+		return
+	}
+
+	// Existing access tokens still carry the old email until they are refreshed
+	c.JSON(http.StatusOK, gin.H{"message": "Email changed successfully. Refresh your token to update your session."})
+}
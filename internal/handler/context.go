@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// principalKey stores the provider-neutral identity of the caller in the gin context.
+const principalKey = "principal"
+
+// SetPrincipal stores the authenticated caller (called by the auth middleware).
+func SetPrincipal(c *gin.Context, principal *domain.Principal) {
+	c.Set(principalKey, principal)
+}
+
+// GetPrincipal retrieves the authenticated caller, whichever token type was used.
+func GetPrincipal(c *gin.Context) (*domain.Principal, bool) {
+	value, exists := c.Get(principalKey)
+	if !exists {
+		return nil, false
+	}
+	principal, ok := value.(*domain.Principal)
+	return principal, ok
+}
+
+// currentUser returns the authenticated caller and their ID in our users table.
+// It writes an error response and returns false if the authentication context is missing,
+// or if the caller has no local account.
+func currentUser(c *gin.Context) (*domain.Principal, uuid.UUID, bool) {
+	principal, ok := GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Authentication context missing"})
+		return nil, uuid.Nil, false
+	}
+	userID, ok := principal.LocalUserID()
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This endpoint requires a local account"})
+		return nil, uuid.Nil, false
+	}
+	return principal, userID, true
+}
+
+// currentUserID returns the ID of the calling user in our users table (see currentUser).
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	_, userID, ok := currentUser(c)
+	return userID, ok
+}
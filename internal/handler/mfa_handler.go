@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// MFAHandler handles HTTP requests for managing the current user's two-factor authentication.
+type MFAHandler struct {
+	MFAService ports.MFAService
+}
+
+// NewMFAHandler creates a new instance of the MFAHandler.
+func NewMFAHandler(mfaService ports.MFAService) *MFAHandler {
+	return &MFAHandler{
+		MFAService: mfaService,
+	}
+}
+
+// EnrollTOTP starts enrolling an authenticator app (POST /api/v1/me/mfa/totp/enroll)
+func (h *MFAHandler) EnrollTOTP(c *gin.Context) {
+	principal, userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.MFAService.EnrollTOTP(c, userID, principal.Email)
+	if err != nil {
+		if err.Error() == "two-factor authentication is already enabled" {
+			c.JSON(http.StatusConflict, gin.H{"error": "Enrollment failed", "details": err.Error()})
+			return
+		}
+		log.Printf("TOTP enrollment error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Enrollment failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmTOTP enables two-factor authentication with the first code (POST /api/v1/me/mfa/totp/confirm)
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ConfirmTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := h.MFAService.ConfirmTOTP(c, userID, req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid two-factor code", "no pending two-factor enrollment", "two-factor authentication is already enabled":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Confirmation failed", "details": err.Error()})
+		default:
+			log.Printf("TOTP confirmation error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Confirmation failed"})
+		}
+		return
+	}
+
+	// Recovery codes are only shown here; the client must ask the user to store them
+	c.JSON(http.StatusOK, recoveryCodes)
+}
+
+// DisableTOTP turns two-factor authentication off (POST /api/v1/me/mfa/totp/disable)
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	principal, userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req domain.DisableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.MFAService.DisableTOTP(c, userID, principal.Email, req); err != nil {
+		if respondTooManyAttempts(c, err) {
+			return
+		}
+		switch err.Error() {
+		case "invalid two-factor code", "two-factor authentication is not enabled":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Disabling two-factor authentication failed", "details": err.Error()})
+		default:
+			log.Printf("TOTP disable error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Disabling two-factor authentication failed"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled."})
+}
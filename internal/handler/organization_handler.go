@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// OrganizationHandler handles HTTP requests of the organizations (shared workspaces) API.
+// Permissions depend on the caller's role in each organization and are checked by the service.
+type OrganizationHandler struct {
+	OrgService ports.OrganizationService
+	// DebugMode echoes invitation tokens back as debug_token (see config.DebugMode).
+	DebugMode bool
+}
+
+// NewOrganizationHandler creates a new instance of the OrganizationHandler.
+func NewOrganizationHandler(orgService ports.OrganizationService, debugMode bool) *OrganizationHandler {
+	return &OrganizationHandler{
+		OrgService: orgService,
+		DebugMode:  debugMode,
+	}
+}
+
+// ListOrganizations returns the caller's organizations with their role (GET /api/v1/orgs)
+func (h *OrganizationHandler) ListOrganizations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	orgs, err := h.OrgService.ListOrganizations(c, userID)
+	if err != nil {
+		respondOrganizationError(c, "Failed to list organizations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"organizations": orgs})
+}
+
+// CreateOrganization creates an organization owned by the caller (POST /api/v1/orgs)
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	org, err := h.OrgService.CreateOrganization(c, userID, req)
+	if err != nil {
+		respondOrganizationError(c, "Failed to create organization", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// GetOrganization returns one of the caller's organizations (GET /api/v1/orgs/:id)
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	actorID, orgID, ok := organizationTarget(c)
+	if !ok {
+		return
+	}
+
+	org, err := h.OrgService.GetOrganization(c, actorID, orgID)
+	if err != nil {
+		respondOrganizationError(c, "Failed to get organization", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// RenameOrganization changes the organization's name (PATCH /api/v1/orgs/:id)
+func (h *OrganizationHandler) RenameOrganization(c *gin.Context) {
+	actorID, orgID, ok := organizationTarget(c)
+	if !ok {
+		return
+	}
+
+	var req domain.OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	org, err := h.OrgService.RenameOrganization(c, actorID, orgID, req)
+	if err != nil {
+		respondOrganizationError(c, "Failed to update organization", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// DeleteOrganization deletes the organization with its members and invitations (DELETE /api/v1/orgs/:id)
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	actorID, orgID, ok := organizationTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.OrgService.DeleteOrganization(c, actorID, orgID); err != nil {
+		respondOrganizationError(c, "Failed to delete organization", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Organization deleted"})
+}
+
+// ListMembers returns the organization's members (GET /api/v1/orgs/:id/members)
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	actorID, orgID, ok := organizationTarget(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.OrgService.ListMembers(c, actorID, orgID)
+	if err != nil {
+		respondOrganizationError(c, "Failed to list members", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// UpdateMemberRole changes a member's role (PUT /api/v1/orgs/:id/members/:userId)
+func (h *OrganizationHandler) UpdateMemberRole(c *gin.Context) {
+	actorID, orgID, ok := organizationTarget(c)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req domain.UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.OrgService.UpdateMemberRole(c, actorID, orgID, userID, req); err != nil {
+		respondOrganizationError(c, "Failed to update member role", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member role updated"})
+}
+
+// RemoveMember removes a member, or lets the caller leave (DELETE /api/v1/orgs/:id/members/:userId)
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	actorID, orgID, ok := organizationTarget(c)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.OrgService.RemoveMember(c, actorID, orgID, userID); err != nil {
+		respondOrganizationError(c, "Failed to remove member", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// ListInvitations returns the pending invitations (GET /api/v1/orgs/:id/invitations)
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	actorID, orgID, ok := organizationTarget(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.OrgService.ListInvitations(c, actorID, orgID)
+	if err != nil {
+		respondOrganizationError(c, "Failed to list invitations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// InviteMember emails an invitation to join the organization (POST /api/v1/orgs/:id/invitations)
+func (h *OrganizationHandler) InviteMember(c *gin.Context) {
+	actorID, orgID, ok := organizationTarget(c)
+	if !ok {
+		return
+	}
+
+	var req domain.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	invitation, token, err := h.OrgService.InviteMember(c, actorID, orgID, req)
+	if err != nil {
+		respondOrganizationError(c, "Failed to invite member", err)
+		return
+	}
+
+	// The token is only echoed back in debug mode; otherwise it is only in the email
+	response := gin.H{"invitation": invitation}
+	if h.DebugMode {
+		response["debug_token"] = token
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// RevokeInvitation deletes a pending invitation (DELETE /api/v1/orgs/:id/invitations/:invitationId)
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	actorID, orgID, ok := organizationTarget(c)
+	if !ok {
+		return
+	}
+	invitationID, err := uuid.Parse(c.Param("invitationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+
+	if err := h.OrgService.RevokeInvitation(c, actorID, orgID, invitationID); err != nil {
+		respondOrganizationError(c, "Failed to revoke invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// AcceptInvitation joins the organization of an invitation token (POST /api/v1/orgs/invitations/accept)
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.InvitationTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	org, err := h.OrgService.AcceptInvitation(c, userID, req)
+	if err != nil {
+		respondOrganizationError(c, "Failed to accept invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// DeclineInvitation deletes the invitation of a token; no login is needed (POST /api/v1/orgs/invitations/decline)
+func (h *OrganizationHandler) DeclineInvitation(c *gin.Context) {
+	var req domain.InvitationTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.OrgService.DeclineInvitation(c, req); err != nil {
+		respondOrganizationError(c, "Failed to decline invitation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation declined"})
+}
+
+// organizationTarget returns the ID of the calling user and the organization ID from the path.
+// It writes an error response and returns false if either is missing or invalid.
+func organizationTarget(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	orgID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, orgID, true
+}
+
+// respondOrganizationError maps the errors of the OrganizationService to HTTP responses.
+func respondOrganizationError(c *gin.Context, message string, err error) {
+	switch err.Error() {
+	case "organization not found", "member not found", "invitation not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": message, "details": err.Error()})
+	case "insufficient organization role", "invitation was sent to a different email address", "email address must be verified to accept an invitation":
+		c.JSON(http.StatusForbidden, gin.H{"error": message, "details": err.Error()})
+	case "user is already a member":
+		c.JSON(http.StatusConflict, gin.H{"error": message, "details": err.Error()})
+	case "an organization must keep at least one owner", "organization name must not be empty", "invalid organization role", "invalid or expired invitation":
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "details": err.Error()})
+	default:
+		log.Printf("Organization error (%s): %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
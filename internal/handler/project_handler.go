@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// ProjectHandler handles HTTP requests of the projects API. The :id path parameter accepts the
+// project ID or its key. The routes check the system permissions; the service checks the project role.
+type ProjectHandler struct {
+	ProjectService ports.ProjectService
+}
+
+// NewProjectHandler creates a new instance of the ProjectHandler.
+func NewProjectHandler(projectService ports.ProjectService) *ProjectHandler {
+	return &ProjectHandler{ProjectService: projectService}
+}
+
+// ListProjects returns a page of the caller's projects (GET /api/v1/projects)
+func (h *ProjectHandler) ListProjects(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ListProjectsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	projects, err := h.ProjectService.ListProjects(c, userID, req)
+	if err != nil {
+		respondProjectError(c, "Failed to list projects", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, projects)
+}
+
+// CreateProject creates a project owned by the caller (POST /api/v1/projects)
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	project, err := h.ProjectService.CreateProject(c, userID, req)
+	if err != nil {
+		respondProjectError(c, "Failed to create project", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+// GetProject returns one of the caller's projects (GET /api/v1/projects/:id)
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	project, err := h.ProjectService.GetProject(c, actorID, c.Param("id"))
+	if err != nil {
+		respondProjectError(c, "Failed to get project", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// UpdateProject changes the project's details (PATCH /api/v1/projects/:id)
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	project, err := h.ProjectService.UpdateProject(c, actorID, c.Param("id"), req)
+	if err != nil {
+		respondProjectError(c, "Failed to update project", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// ArchiveProject makes the project read-only (POST /api/v1/projects/:id/archive)
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// UnarchiveProject makes an archived project editable again (POST /api/v1/projects/:id/unarchive)
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *ProjectHandler) setArchived(c *gin.Context, archived bool) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	project, err := h.ProjectService.SetProjectArchived(c, actorID, c.Param("id"), archived)
+	if err != nil {
+		respondProjectError(c, "Failed to update project", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// DeleteProject permanently deletes the project with its members (DELETE /api/v1/projects/:id)
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.ProjectService.DeleteProject(c, actorID, c.Param("id")); err != nil {
+		respondProjectError(c, "Failed to delete project", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Project deleted"})
+}
+
+// ListMembers returns the project's members (GET /api/v1/projects/:id/members)
+func (h *ProjectHandler) ListMembers(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.ProjectService.ListMembers(c, actorID, c.Param("id"))
+	if err != nil {
+		respondProjectError(c, "Failed to list members", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// AddMember adds an existing user to the project (POST /api/v1/projects/:id/members)
+func (h *ProjectHandler) AddMember(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.AddProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	member, err := h.ProjectService.AddMember(c, actorID, c.Param("id"), req)
+	if err != nil {
+		respondProjectError(c, "Failed to add member", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateMemberRole changes a member's project role (PUT /api/v1/projects/:id/members/:userId)
+func (h *ProjectHandler) UpdateMemberRole(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req domain.UpdateProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	if err := h.ProjectService.UpdateMemberRole(c, actorID, c.Param("id"), userID, req); err != nil {
+		respondProjectError(c, "Failed to update member role", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member role updated"})
+}
+
+// RemoveMember removes a member, or lets the caller leave (DELETE /api/v1/projects/:id/members/:userId)
+func (h *ProjectHandler) RemoveMember(c *gin.Context) {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.ProjectService.RemoveMember(c, actorID, c.Param("id"), userID); err != nil {
+		respondProjectError(c, "Failed to remove member", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// respondProjectError maps the errors of the ProjectService to HTTP responses.
+func respondProjectError(c *gin.Context, message string, err error) {
+	switch err.Error() {
+	case "project not found", "member not found", "user not found":
+		c.JSON(http.StatusNotFound, gin.H{"error": message, "details": err.Error()})
+	case "insufficient project role":
+		c.JSON(http.StatusForbidden, gin.H{"error": message, "details": err.Error()})
+	case "project is archived", "project key already in use", "user is already a project member":
+		c.JSON(http.StatusConflict, gin.H{"error": message, "details": err.Error()})
+	case "invalid project key", "project name must not be empty", "dates must use the format YYYY-MM-DD",
+		"end date must not be before start date", "cannot change the role of the project owner", "cannot remove the project owner",
+		"a project must keep at least one admin":
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "details": err.Error()})
+	default:
+		log.Printf("Project error (%s): %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// SessionHandler handles HTTP requests for listing and revoking the current user's sessions.
+type SessionHandler struct {
+	SessionService ports.SessionService
+}
+
+// NewSessionHandler creates a new instance of the SessionHandler.
+func NewSessionHandler(sessionService ports.SessionService) *SessionHandler {
+	return &SessionHandler{
+		SessionService: sessionService,
+	}
+}
+
+// ListSessions returns the devices the user is signed in on (GET /api/v1/me/sessions)
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	principal, userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.SessionService.ListSessions(c, userID, principal.SessionID)
+	if err != nil {
+		log.Printf("Session list error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession signs the user out of one device (DELETE /api/v1/me/sessions/:id)
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return
+	}
+
+	if err := h.SessionService.RevokeSession(c, userID, sessionID); err != nil {
+		if err.Error() == "session not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		log.Printf("Session revoke error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
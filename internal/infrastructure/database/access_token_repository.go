@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// accessTokenColumns is the column list matching scanAccessToken.
+const accessTokenColumns = `id, user_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, created_at, revoked_at`
+
+// PersonalAccessTokenRepository implements the ports.PersonalAccessTokenRepository interface for Postgres (Supabase).
+type PersonalAccessTokenRepository struct {
+	DB *sql.DB
+}
+
+// NewPersonalAccessTokenRepository creates a new instance of the PersonalAccessTokenRepository.
+func NewPersonalAccessTokenRepository(db *sql.DB) ports.PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{DB: db}
+}
+
+// CreateToken saves a new token record to the 'personal_access_tokens' table.
+func (r *PersonalAccessTokenRepository) CreateToken(ctx context.Context, token domain.PersonalAccessToken) error {
+	query := `
+		INSERT INTO personal_access_tokens (id, user_id, name, token_prefix, token_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.DB.ExecContext(
+		ctx,
+		query,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.TokenPrefix,
+		token.TokenHash,
+		pq.Array(token.Scopes),
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+// GetTokenByHash retrieves a token by its SHA-256 hash.
+func (r *PersonalAccessTokenRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	query := `SELECT ` + accessTokenColumns + ` FROM personal_access_tokens WHERE token_hash = $1`
+	token, err := scanAccessToken(r.DB.QueryRowContext(ctx, query, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Token not found
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// ListTokens returns the user's tokens that have not been revoked, newest first.
+func (r *PersonalAccessTokenRepository) ListTokens(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	query := `
+		SELECT ` + accessTokenColumns + `
+		FROM personal_access_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []domain.PersonalAccessToken{}
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// RevokeToken marks the token as revoked; it is rejected from the next request on.
+func (r *PersonalAccessTokenRepository) RevokeToken(ctx context.Context, userID uuid.UUID, tokenID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE personal_access_tokens SET revoked_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL
+	`
+	result, err := r.DB.ExecContext(ctx, query, time.Now(), tokenID, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// TouchToken updates last_used_at at most once per minInterval to avoid a write on every request.
+func (r *PersonalAccessTokenRepository) TouchToken(ctx context.Context, tokenID uuid.UUID, minInterval time.Duration) error {
+	now := time.Now()
+	query := `
+		UPDATE personal_access_tokens SET last_used_at = $1
+		WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3)
+	`
+	_, err := r.DB.ExecContext(ctx, query, now, tokenID, now.Add(-minInterval))
+	return err
+}
+
+// scanAccessToken reads one row selected with accessTokenColumns.
+func scanAccessToken(row interface{ Scan(dest ...any) error }) (*domain.PersonalAccessToken, error) {
+	token := &domain.PersonalAccessToken{}
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.TokenPrefix,
+		&token.TokenHash,
+		pq.Array(&token.Scopes),
+		&expiresAt,
+		&lastUsedAt,
+		&token.CreatedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return token, nil
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// AttemptStore implements the ports.AttemptStore interface for Postgres (Supabase).
+type AttemptStore struct {
+	DB *sql.DB
+}
+
+// NewAttemptStore creates a new instance of the AttemptStore.
+func NewAttemptStore(db *sql.DB) ports.AttemptStore {
+	return &AttemptStore{DB: db}
+}
+
+// RecordFailure atomically increments the counter in the 'auth_attempts' table.
+func (s *AttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	now := time.Now()
+	query := `
+		INSERT INTO auth_attempts (key, failures, last_failure_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE
+		SET failures = CASE
+				WHEN auth_attempts.last_failure_at < $3 THEN 1
+				ELSE auth_attempts.failures + 1
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures
+	`
+	var failures int
+	err := s.DB.QueryRowContext(ctx, query, key, now, now.Add(-window)).Scan(&failures)
+	if err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
+
+// SetLockout sets the time until which the key is locked out.
+func (s *AttemptStore) SetLockout(ctx context.Context, key string, until time.Time) error {
+	query := `UPDATE auth_attempts SET locked_until = $1 WHERE key = $2`
+	_, err := s.DB.ExecContext(ctx, query, until, key)
+	return err
+}
+
+// GetLockedUntil returns the furthest lockout that is still active for any of the keys.
+func (s *AttemptStore) GetLockedUntil(ctx context.Context, keys ...string) (time.Time, error) {
+	query := `
+		SELECT MAX(locked_until) FROM auth_attempts
+		WHERE key = ANY($1) AND locked_until > $2
+	`
+	var lockedUntil sql.NullTime
+	err := s.DB.QueryRowContext(ctx, query, pq.Array(keys), time.Now()).Scan(&lockedUntil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !lockedUntil.Valid {
+		return time.Time{}, nil
+	}
+	return lockedUntil.Time, nil
+}
+
+// ClearFailures deletes the counter row of a key.
+func (s *AttemptStore) ClearFailures(ctx context.Context, key string) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM auth_attempts WHERE key = $1`, key)
+	return err
+}
+
+// PruneExpired deletes the rows RecordFailure would start again from 1 and that lock out nothing.
+func (s *AttemptStore) PruneExpired(ctx context.Context, window time.Duration) (int64, error) {
+	now := time.Now()
+	query := `
+		DELETE FROM auth_attempts
+		WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)
+	`
+	result, err := s.DB.ExecContext(ctx, query, now.Add(-window), now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
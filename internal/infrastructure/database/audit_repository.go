@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// AuditRepository implements the ports.AuditRepository interface for Postgres (Supabase).
+type AuditRepository struct {
+	DB *sql.DB
+}
+
+// NewAuditRepository creates a new instance of the AuditRepository.
+func NewAuditRepository(db *sql.DB) ports.AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+// auditChainLockID is the transaction-level advisory lock that serializes appends to the hash chain.
+const auditChainLockID = 7_215_004_611
+
+// auditEventColumns lists the 'audit_events' columns read by scanAuditEvent, in order.
+const auditEventColumns = `id, occurred_at, event_type, outcome, actor_id, subject_id, email, ip, user_agent, details,
+	COALESCE(prev_hash, ''), COALESCE(hash, '')`
+
+// Record inserts the event into the 'audit_events' table. uuid.Nil actor/subject IDs are stored as NULL.
+// Appends are serialized with an advisory lock, so every event is chained to the one inserted just before it.
+func (r *AuditRepository) Record(ctx context.Context, event domain.AuditEvent) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	// Store exactly what is hashed (Postgres keeps microseconds)
+	event.OccurredAt = event.OccurredAt.UTC().Truncate(time.Microsecond)
+	details, err := json.Marshal(nonNilDetails(event.Details))
+	if err != nil {
+		return fmt.Errorf("failed to encode audit details: %w", err)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLockID); err != nil {
+		return err
+	}
+
+	// Rows from before hash chaining have a NULL hash; the chain then starts from the empty hash
+	lastQuery := `SELECT COALESCE(hash, '') FROM audit_events ORDER BY id DESC LIMIT 1`
+	err = tx.QueryRowContext(ctx, lastQuery).Scan(&event.PrevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	event.Hash = event.ComputeHash()
+
+	query := `
+		INSERT INTO audit_events (occurred_at, event_type, outcome, actor_id, subject_id, email, ip, user_agent, details, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		event.OccurredAt,
+		event.Type,
+		event.Outcome,
+		nullUUID(event.ActorID),
+		nullUUID(event.SubjectID),
+		event.Email,
+		event.IP,
+		event.UserAgent,
+		details,
+		event.PrevHash,
+		event.Hash,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListEvents builds the WHERE clause from the filter and runs a count and a page query.
+func (r *AuditRepository) ListEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, int, error) {
+	conditions := []string{"TRUE"}
+	args := []any{}
+	addArg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.UserID != uuid.Nil {
+		userID := addArg(filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("(actor_id = %s OR subject_id = %s)", userID, userID))
+	}
+	if filter.Email != "" {
+		conditions = append(conditions, fmt.Sprintf("LOWER(email) = LOWER(%s)", addArg(filter.Email)))
+	}
+	if filter.Type != "" {
+		conditions = append(conditions, fmt.Sprintf("event_type = %s", addArg(filter.Type)))
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= %s", addArg(filter.From)))
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("occurred_at < %s", addArg(filter.To)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_events WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events WHERE ` + where +
+		fmt.Sprintf(` ORDER BY occurred_at DESC, id DESC LIMIT %s OFFSET %s`, addArg(filter.Limit), addArg(filter.Offset))
+	events, err := r.queryEvents(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// ListEventsAfter returns the next batch of events in chain order, for walking the whole log.
+func (r *AuditRepository) ListEventsAfter(ctx context.Context, afterID int64, limit int) ([]domain.AuditEvent, error) {
+	query := `SELECT ` + auditEventColumns + ` FROM audit_events WHERE id > $1 ORDER BY id LIMIT $2`
+	return r.queryEvents(ctx, query, afterID, limit)
+}
+
+// queryEvents runs a query selecting auditEventColumns and scans every row.
+func (r *AuditRepository) queryEvents(ctx context.Context, query string, args ...any) ([]domain.AuditEvent, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []domain.AuditEvent{}
+	for rows.Next() {
+		var event domain.AuditEvent
+		var actorID, subjectID uuid.NullUUID
+		var details []byte
+		if err := rows.Scan(
+			&event.ID,
+			&event.OccurredAt,
+			&event.Type,
+			&event.Outcome,
+			&actorID,
+			&subjectID,
+			&event.Email,
+			&event.IP,
+			&event.UserAgent,
+			&details,
+			&event.PrevHash,
+			&event.Hash,
+		); err != nil {
+			return nil, err
+		}
+		event.ActorID = actorID.UUID
+		event.SubjectID = subjectID.UUID
+		if err := json.Unmarshal(details, &event.Details); err != nil {
+			return nil, fmt.Errorf("failed to decode audit details: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// nullUUID maps uuid.Nil to SQL NULL.
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
+
+// nonNilDetails makes sure empty details are stored as {} rather than null.
+func nonNilDetails(details map[string]string) map[string]string {
+	if details == nil {
+		return map[string]string{}
+	}
+	return details
+}
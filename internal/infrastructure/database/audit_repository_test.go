@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+	"github.com/mitcheltastic/ManproBackend/internal/service"
+	"github.com/pressly/goose/v3"
+)
+
+// openTestDB connects to DATABASE_URL and migrates a throwaway schema, dropped when the test ends,
+// so the test never touches the application's tables. The test is skipped without DATABASE_URL.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("DATABASE_URL is not set; skipping the database integration test")
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	// One connection, so the search_path set below applies to every query
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := db.Exec(fmt.Sprintf(`CREATE SCHEMA %s`, schema)); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := db.Exec(fmt.Sprintf(`DROP SCHEMA %s CASCADE`, schema)); err != nil {
+			t.Errorf("drop schema %s: %v", schema, err)
+		}
+	})
+	if _, err := db.Exec(fmt.Sprintf(`SET search_path TO %s, public`, schema)); err != nil {
+		t.Fatalf("set search_path: %v", err)
+	}
+
+	goose.SetLogger(goose.NopLogger())
+	if err := goose.SetDialect("postgres"); err != nil {
+		t.Fatalf("goose dialect: %v", err)
+	}
+	if err := goose.Up(db, filepath.Join("..", "..", "..", "scripts", "migrations")); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// recordTestEvents appends n events through the repository and returns their IDs in chain order.
+func recordTestEvents(t *testing.T, repo ports.AuditRepository, n int) []int64 {
+	t.Helper()
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		event := domain.AuditEvent{
+			Type:      domain.AuditEventLogin,
+			Outcome:   domain.AuditOutcomeSuccess,
+			ActorID:   uuid.New(),
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			IP:        "192.0.2.1",
+			UserAgent: "audit-chain-test",
+			Details:   map[string]string{"index": fmt.Sprint(i)},
+		}
+		if err := repo.Record(ctx, event); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	events, err := repo.ListEventsAfter(ctx, 0, n)
+	if err != nil {
+		t.Fatalf("ListEventsAfter: %v", err)
+	}
+	if len(events) != n {
+		t.Fatalf("stored %d events, want %d", len(events), n)
+	}
+	ids := make([]int64, n)
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+func TestAuditChainVerifiesRecordedEvents(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewAuditRepository(db)
+	ids := recordTestEvents(t, repo, 5)
+
+	report, err := service.VerifyAuditChain(context.Background(), repo, nil)
+	if err != nil {
+		t.Fatalf("VerifyAuditChain: %v", err)
+	}
+	if !report.Intact() {
+		t.Fatalf("chain reported broken at %d: %s", report.BrokenAtID, report.Problem)
+	}
+	if report.CheckedEvents != 5 || report.LastID != ids[4] {
+		t.Errorf("report = %+v, want 5 checked events ending at %d", *report, ids[4])
+	}
+
+	// The append-only triggers reject changes to the log
+	if _, err := db.Exec(`UPDATE audit_events SET email = 'x@example.com' WHERE id = $1`, ids[2]); err == nil {
+		t.Error("audit_events accepted an UPDATE")
+	}
+}
+
+func TestAuditChainReportsTampering(t *testing.T) {
+	tests := []struct {
+		name     string
+		tamper   string
+		brokenAt int // index of the event the break must be reported at
+		problem  string
+	}{
+		{"modified event", `UPDATE audit_events SET details = '{"index": "forged"}' WHERE id = $1`, 2, "was modified"},
+		{"deleted event", `DELETE FROM audit_events WHERE id = $1`, 3, "was removed or reordered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			repo := NewAuditRepository(db)
+			ids := recordTestEvents(t, repo, 5)
+
+			// Only the table owner can do this; it stands in for someone with direct database access
+			if _, err := db.Exec(`ALTER TABLE audit_events DISABLE TRIGGER USER`); err != nil {
+				t.Fatalf("disable triggers: %v", err)
+			}
+			if _, err := db.Exec(tt.tamper, ids[2]); err != nil {
+				t.Fatalf("tamper: %v", err)
+			}
+
+			report, err := service.VerifyAuditChain(context.Background(), repo, nil)
+			if err != nil {
+				t.Fatalf("VerifyAuditChain: %v", err)
+			}
+			if report.BrokenAtID != ids[tt.brokenAt] {
+				t.Fatalf("BrokenAtID = %d, want %d (problem %q)", report.BrokenAtID, ids[tt.brokenAt], report.Problem)
+			}
+			if !strings.Contains(report.Problem, tt.problem) {
+				t.Errorf("Problem = %q, want it to mention %q", report.Problem, tt.problem)
+			}
+		})
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
 	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+	"github.com/mitcheltastic/ManproBackend/internal/pkg/security"
 )
 
 // AuthRepository implements the ports.AuthRepository interface for Postgres (Supabase).
@@ -20,19 +25,31 @@ func NewAuthRepository(db *sql.DB) ports.AuthRepository {
 	return &AuthRepository{DB: db}
 }
 
-// CreateUser saves a new user record to the 'users' table.
+// userColumns lists the 'users' columns read by scanUser, in order.
+// hashed_password is NULL for accounts created through Firebase sign-in.
+const userColumns = `id, name, email, COALESCE(hashed_password, ''), firebase_uid, is_verified, disabled_at, created_at, updated_at`
+
+// CreateUser inserts the user and grants domain.DefaultRole in one transaction,
+// so an account never exists without its default role.
 func (r *AuthRepository) CreateUser(ctx context.Context, user domain.User) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO users (id, name, email, hashed_password, is_verified, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, name, email, hashed_password, firebase_uid, is_verified, created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8)
 	`
-	_, err := r.DB.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		query,
 		user.ID,
 		user.Name,
 		user.Email,
 		user.HashedPassword,
+		user.FirebaseUID,
 		user.IsVerified,
 		user.CreatedAt,
 		user.UpdatedAt,
@@ -41,32 +58,90 @@ func (r *AuthRepository) CreateUser(ctx context.Context, user domain.User) error
 		// In a real app, you would check for unique constraint violations here (e.g., duplicate email)
 		return err 
 	}
-	return nil
+
+	roleQuery := `INSERT INTO user_roles (user_id, role) VALUES ($1, $2)`
+	if _, err := tx.ExecContext(ctx, roleQuery, user.ID, domain.DefaultRole); err != nil {
+		return fmt.Errorf("failed to assign default role: %w", err)
+	}
+
+	return tx.Commit()
 }
 
 // GetUserByEmail retrieves a user by their email address.
 func (r *AuthRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+	return scanUser(r.DB.QueryRowContext(ctx, query, email))
+}
+
+// GetUserByID retrieves a user by their ID.
+func (r *AuthRepository) GetUserByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return scanUser(r.DB.QueryRowContext(ctx, query, userID))
+}
+
+// GetUserByFirebaseUID retrieves the user linked to a Firebase account.
+func (r *AuthRepository) GetUserByFirebaseUID(ctx context.Context, firebaseUID string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE firebase_uid = $1`
+	return scanUser(r.DB.QueryRowContext(ctx, query, firebaseUID))
+}
+
+// LinkFirebaseUID stores the Firebase UID on an existing user.
+// It only succeeds if the user is not yet linked to a different Firebase account.
+func (r *AuthRepository) LinkFirebaseUID(ctx context.Context, userID uuid.UUID, firebaseUID string) error {
 	query := `
-		SELECT id, name, email, hashed_password, is_verified, created_at, updated_at 
-		FROM users WHERE email = $1
+		UPDATE users SET firebase_uid = $1, updated_at = $2
+		WHERE id = $3 AND (firebase_uid IS NULL OR firebase_uid = $1)
 	`
+	result, err := r.DB.ExecContext(ctx, query, firebaseUID, time.Now(), userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("user is already linked to another Firebase account")
+	}
+	return nil
+}
+
+// scanUser reads a single row selected with userColumns. Returns nil, nil if there is no row.
+func scanUser(row *sql.Row) (*domain.User, error) {
+	user, err := scanUserFields(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // User not found
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// scanUserFields reads the userColumns of a *sql.Row or *sql.Rows.
+func scanUserFields(row interface{ Scan(dest ...any) error }) (*domain.User, error) {
 	user := &domain.User{}
-	err := r.DB.QueryRowContext(ctx, query, email).Scan(
+	var firebaseUID sql.NullString
+	var disabledAt sql.NullTime
+	err := row.Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
 		&user.HashedPassword,
+		&firebaseUID,
 		&user.IsVerified,
+		&disabledAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
-
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil // User not found
-		}
 		return nil, err
 	}
+
+	user.FirebaseUID = firebaseUID.String
+	if disabledAt.Valid {
+		user.DisabledAt = &disabledAt.Time
+	}
 	return user, nil
 }
 
@@ -79,56 +154,419 @@ func (r *AuthRepository) UpdateUserPassword(ctx context.Context, userID string,
 	return err
 }
 
+// ReplacePasswordHash upgrades the stored hash unless the password changed since it was read.
+// updated_at is left alone: the password itself is the same.
+func (r *AuthRepository) ReplacePasswordHash(ctx context.Context, userID uuid.UUID, oldHash string, newHash string) error {
+	query := `
+		UPDATE users SET hashed_password = $1 WHERE id = $2 AND hashed_password = $3
+	`
+	_, err := r.DB.ExecContext(ctx, query, newHash, userID, oldHash)
+	return err
+}
+
 // --- Password Reset Logic (Requires a temporary 'password_reset_tokens' table) ---
 
-// CreatePasswordResetCode saves a unique 6-digit code linked to a user/email.
-func (r *AuthRepository) CreatePasswordResetCode(ctx context.Context, email string, code string) error {
-	// IMPORTANT: You will need to create a table in Supabase like:
-	// CREATE TABLE password_reset_tokens (email TEXT PRIMARY KEY, code TEXT, expires_at TIMESTAMP WITH TIME ZONE);
-	
+// CreatePasswordResetCode saves the keyed hash of a 6-digit code linked to a user/email.
+// The plaintext code is never stored, so read access to the database is not enough to reset a password.
+func (r *AuthRepository) CreatePasswordResetCode(ctx context.Context, email string, codeHash string) error {
 	// Upsert (insert or update) the code, setting it to expire in 15 minutes.
+	// Issuing a new code also resets the wrong-guess counter.
 	query := `
-		INSERT INTO password_reset_tokens (email, code, expires_at)
+		INSERT INTO password_reset_tokens (email, code_hash, expires_at)
 		VALUES ($1, $2, $3)
 		ON CONFLICT (email) DO UPDATE 
-		SET code = EXCLUDED.code, expires_at = EXCLUDED.expires_at
+		SET code_hash = EXCLUDED.code_hash, expires_at = EXCLUDED.expires_at, attempts = 0
 	`
 	expiresAt := time.Now().Add(15 * time.Minute)
-	_, err := r.DB.ExecContext(ctx, query, email, code, expiresAt)
+	_, err := r.DB.ExecContext(ctx, query, email, codeHash, expiresAt)
 	return err
 }
 
-// VerifyPasswordResetCode checks if the code is valid and not expired.
-func (r *AuthRepository) VerifyPasswordResetCode(ctx context.Context, email string, code string) error {
+// ConsumePasswordResetCode checks the code hash in constant time and deletes the code if it matches.
+// The row is locked for the duration of the check, so two concurrent resets with the same
+// code cannot both succeed. Every wrong guess is counted; after maxAttempts the code is deleted.
+func (r *AuthRepository) ConsumePasswordResetCode(ctx context.Context, email string, codeHash string, maxAttempts int) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
-		SELECT code, expires_at FROM password_reset_tokens WHERE email = $1
+		SELECT code_hash, expires_at, attempts FROM password_reset_tokens WHERE email = $1 FOR UPDATE
 	`
-	var storedCode string
+	var storedHash string
 	var expiresAt time.Time
-	
-	err := r.DB.QueryRowContext(ctx, query, email).Scan(&storedCode, &expiresAt)
-	
+	var attempts int
+
+	err = tx.QueryRowContext(ctx, query, email).Scan(&storedHash, &expiresAt, &attempts)
 	if errors.Is(err, sql.ErrNoRows) {
 		return errors.New("reset code not found for this email")
 	}
 	if err != nil {
 		return err
 	}
-	
-	if storedCode != code {
-		return errors.New("invalid verification code")
-	}
+
+	deleteQuery := `DELETE FROM password_reset_tokens WHERE email = $1`
 
 	if expiresAt.Before(time.Now()) {
+		if _, err := tx.ExecContext(ctx, deleteQuery, email); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
 		return errors.New("verification code expired")
 	}
 
-	return nil // Code is valid and not expired
+	if !security.EqualHashes(storedHash, codeHash) {
+		attempts++
+		if attempts >= maxAttempts {
+			if _, err := tx.ExecContext(ctx, deleteQuery, email); err != nil {
+				return err
+			}
+			if err := tx.Commit(); err != nil {
+				return err
+			}
+			return errors.New("too many invalid attempts, please request a new code")
+		}
+
+		countQuery := `UPDATE password_reset_tokens SET attempts = $1 WHERE email = $2`
+		if _, err := tx.ExecContext(ctx, countQuery, attempts, email); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		return errors.New("invalid verification code")
+	}
+
+	// Code is valid and not expired: consume it
+	if _, err := tx.ExecContext(ctx, deleteQuery, email); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// DeletePasswordResetCode removes the code after a successful reset.
+// DeletePasswordResetCode removes any pending reset code for the email.
 func (r *AuthRepository) DeletePasswordResetCode(ctx context.Context, email string) error {
 	query := `DELETE FROM password_reset_tokens WHERE email = $1`
 	_, err := r.DB.ExecContext(ctx, query, email)
 	return err
-}
\ No newline at end of file
+}
+
+// --- Email Verification Logic ('email_verification_tokens' table) ---
+
+// MarkUserVerified sets is_verified to true for the given user.
+func (r *AuthRepository) MarkUserVerified(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users SET is_verified = TRUE, updated_at = $1 WHERE id = $2
+	`
+	_, err := r.DB.ExecContext(ctx, query, time.Now(), userID)
+	return err
+}
+
+// ReplaceEmailVerificationToken stores a new token hash, so only the most recently sent token is valid.
+func (r *AuthRepository) ReplaceEmailVerificationToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM email_verification_tokens WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO email_verification_tokens (token_hash, user_id, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.ExecContext(ctx, query, tokenHash, userID, expiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ConsumeEmailVerificationToken deletes the token and returns its owner in a single statement,
+// so the same token can never be used twice.
+func (r *AuthRepository) ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	query := `
+		DELETE FROM email_verification_tokens
+		WHERE token_hash = $1 AND expires_at > $2
+		RETURNING user_id
+	`
+	var userID uuid.UUID
+	err := r.DB.QueryRowContext(ctx, query, tokenHash, time.Now()).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, nil // Token not found or expired
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+// --- Magic Link Logic ('magic_link_tokens' table) ---
+
+// ReplaceMagicLinkToken stores a new token hash, so only the most recently sent link is valid.
+func (r *AuthRepository) ReplaceMagicLinkToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM magic_link_tokens WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO magic_link_tokens (token_hash, user_id, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.ExecContext(ctx, query, tokenHash, userID, expiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ConsumeMagicLinkToken deletes the token and returns its owner in a single statement,
+// so the same link can never be used twice.
+func (r *AuthRepository) ConsumeMagicLinkToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	query := `
+		DELETE FROM magic_link_tokens
+		WHERE token_hash = $1 AND expires_at > $2
+		RETURNING user_id
+	`
+	var userID uuid.UUID
+	err := r.DB.QueryRowContext(ctx, query, tokenHash, time.Now()).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, nil // Token not found or expired
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+// --- Email Change Logic ('email_change_requests' table) ---
+
+// CreateEmailChangeCode saves the pending change, replacing any earlier request and resetting the wrong-guess counter.
+func (r *AuthRepository) CreateEmailChangeCode(ctx context.Context, userID uuid.UUID, newEmail string, codeHash string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO email_change_requests (user_id, new_email, code_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET new_email = EXCLUDED.new_email, code_hash = EXCLUDED.code_hash, expires_at = EXCLUDED.expires_at,
+		    attempts = 0, created_at = NOW()
+	`
+	_, err := r.DB.ExecContext(ctx, query, userID, newEmail, codeHash, expiresAt)
+	return err
+}
+
+// ConsumeEmailChangeCode checks the code like ConsumePasswordResetCode: the row is locked for the
+// check, wrong guesses are counted, and the request is deleted once used or after maxAttempts.
+func (r *AuthRepository) ConsumeEmailChangeCode(ctx context.Context, userID uuid.UUID, codeHash string, maxAttempts int) (string, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT new_email, code_hash, expires_at, attempts FROM email_change_requests WHERE user_id = $1 FOR UPDATE
+	`
+	var newEmail, storedHash string
+	var expiresAt time.Time
+	var attempts int
+
+	err = tx.QueryRowContext(ctx, query, userID).Scan(&newEmail, &storedHash, &expiresAt, &attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", errors.New("no pending email change")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	deleteQuery := `DELETE FROM email_change_requests WHERE user_id = $1`
+
+	if expiresAt.Before(time.Now()) {
+		if _, err := tx.ExecContext(ctx, deleteQuery, userID); err != nil {
+			return "", err
+		}
+		if err := tx.Commit(); err != nil {
+			return "", err
+		}
+		return "", errors.New("verification code expired")
+	}
+
+	if !security.EqualHashes(storedHash, codeHash) {
+		attempts++
+		if attempts >= maxAttempts {
+			if _, err := tx.ExecContext(ctx, deleteQuery, userID); err != nil {
+				return "", err
+			}
+			if err := tx.Commit(); err != nil {
+				return "", err
+			}
+			return "", errors.New("too many invalid attempts, please request a new code")
+		}
+
+		countQuery := `UPDATE email_change_requests SET attempts = $1 WHERE user_id = $2`
+		if _, err := tx.ExecContext(ctx, countQuery, attempts, userID); err != nil {
+			return "", err
+		}
+		if err := tx.Commit(); err != nil {
+			return "", err
+		}
+		return "", errors.New("invalid verification code")
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteQuery, userID); err != nil {
+		return "", err
+	}
+	return newEmail, tx.Commit()
+}
+
+// UpdateUserEmail changes the email address in one transaction. A reset code sent to the old
+// address is deleted rather than carried over, so it cannot be used against the new address.
+func (r *AuthRepository) UpdateUserEmail(ctx context.Context, userID uuid.UUID, newEmail string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	deleteQuery := `DELETE FROM password_reset_tokens WHERE email = (SELECT email FROM users WHERE id = $1)`
+	if _, err := tx.ExecContext(ctx, deleteQuery, userID); err != nil {
+		return err
+	}
+
+	// password_reset_tokens.email references users(email) without ON UPDATE CASCADE, so a reset
+	// code requested for the old address in the meantime makes the update fail instead of moving over
+	query := `
+		UPDATE users SET email = $1, is_verified = TRUE, updated_at = $2 WHERE id = $3
+	`
+	if _, err := tx.ExecContext(ctx, query, newEmail, time.Now(), userID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return errors.New("a user with this email already exists")
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// --- Administration ---
+
+// ListUsers builds the WHERE clause from the filter and runs a count and a page query.
+func (r *AuthRepository) ListUsers(ctx context.Context, filter domain.UserFilter) ([]domain.User, int, error) {
+	conditions := []string{"TRUE"}
+	args := []any{}
+	addArg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Query != "" {
+		pattern := addArg("%" + escapeLike(filter.Query) + "%")
+		conditions = append(conditions, fmt.Sprintf("(name ILIKE %s OR email ILIKE %s)", pattern, pattern))
+	}
+	if filter.Role != "" {
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = users.id AND ur.role = %s)", addArg(filter.Role)))
+	}
+	if filter.Verified != nil {
+		conditions = append(conditions, fmt.Sprintf("is_verified = %s", addArg(*filter.Verified)))
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			conditions = append(conditions, "disabled_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "disabled_at IS NULL")
+		}
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE ` + where +
+		fmt.Sprintf(` ORDER BY created_at DESC, id LIMIT %s OFFSET %s`, addArg(filter.Limit), addArg(filter.Offset))
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		user, err := scanUserFields(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, *user)
+	}
+	return users, total, rows.Err()
+}
+
+// SetUserDisabled sets or clears disabled_at. Disabling an already disabled user keeps the original time.
+func (r *AuthRepository) SetUserDisabled(ctx context.Context, userID uuid.UUID, disabled bool) (bool, error) {
+	query := `UPDATE users SET disabled_at = COALESCE(disabled_at, $1), updated_at = $1 WHERE id = $2`
+	if !disabled {
+		query = `UPDATE users SET disabled_at = NULL, updated_at = $1 WHERE id = $2`
+	}
+	result, err := r.DB.ExecContext(ctx, query, time.Now(), userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// DeleteUser removes the user row. Sessions, tokens, roles and other per-user rows are removed by ON DELETE CASCADE.
+func (r *AuthRepository) DeleteUser(ctx context.Context, userID uuid.UUID) (bool, error) {
+	result, err := r.DB.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// ListFirebaseUsers pages through the linked users by ID (keyset pagination), so users added during
+// a long reconciliation run neither shift nor repeat pages.
+func (r *AuthRepository) ListFirebaseUsers(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE firebase_uid IS NOT NULL AND id > $1 ORDER BY id LIMIT $2`
+	rows, err := r.DB.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		user, err := scanUserFields(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern, so user input is matched literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
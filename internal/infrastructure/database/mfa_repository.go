@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// MFARepository implements the ports.MFARepository interface for Postgres (Supabase).
+type MFARepository struct {
+	DB *sql.DB
+}
+
+// NewMFARepository creates a new instance of the MFARepository.
+func NewMFARepository(db *sql.DB) ports.MFARepository {
+	return &MFARepository{DB: db}
+}
+
+// SavePendingTOTP stores an unconfirmed secret. An already enabled enrollment is never overwritten.
+func (r *MFARepository) SavePendingTOTP(ctx context.Context, userID uuid.UUID, secretEncrypted string) error {
+	query := `
+		INSERT INTO user_totp (user_id, secret_encrypted, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret_encrypted = EXCLUDED.secret_encrypted, created_at = EXCLUDED.created_at, last_used_step = 0
+		WHERE user_totp.enabled_at IS NULL
+	`
+	_, err := r.DB.ExecContext(ctx, query, userID, secretEncrypted, time.Now())
+	return err
+}
+
+// GetTOTP retrieves the user's enrollment from the 'user_totp' table.
+func (r *MFARepository) GetTOTP(ctx context.Context, userID uuid.UUID) (*domain.TOTPEnrollment, error) {
+	query := `
+		SELECT user_id, secret_encrypted, enabled_at, last_used_step, created_at
+		FROM user_totp WHERE user_id = $1
+	`
+	enrollment := &domain.TOTPEnrollment{}
+	var enabledAt sql.NullTime
+	err := r.DB.QueryRowContext(ctx, query, userID).Scan(
+		&enrollment.UserID,
+		&enrollment.SecretEncrypted,
+		&enabledAt,
+		&enrollment.LastUsedStep,
+		&enrollment.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not enrolled
+		}
+		return nil, err
+	}
+
+	if enabledAt.Valid {
+		enrollment.EnabledAt = &enabledAt.Time
+	}
+	return enrollment, nil
+}
+
+// EnableTOTP confirms the enrollment and stores a fresh set of recovery code hashes.
+func (r *MFARepository) EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE user_totp SET enabled_at = $1, last_used_step = $2
+		WHERE user_id = $3 AND enabled_at IS NULL
+	`
+	result, err := tx.ExecContext(ctx, query, time.Now(), step, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("no pending two-factor enrollment")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, codeHash := range recoveryCodeHashes {
+		insert := `INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)`
+		if _, err := tx.ExecContext(ctx, insert, userID, codeHash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DisableTOTP deletes the enrollment and the recovery codes.
+func (r *MFARepository) DisableTOTP(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseTOTPStep moves last_used_step forward only if the step is newer, which rejects replayed codes.
+func (r *MFARepository) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE user_totp SET last_used_step = $1
+		WHERE user_id = $2 AND last_used_step < $1
+	`
+	result, err := r.DB.ExecContext(ctx, query, step, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// ConsumeRecoveryCode marks a recovery code as used in a single conditional update.
+func (r *MFARepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`
+	result, err := r.DB.ExecContext(ctx, query, time.Now(), userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
@@ -0,0 +1,388 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// organizationColumns is the column list matching scanOrganization.
+const organizationColumns = `o.id, o.name, o.created_by, o.created_at, o.updated_at`
+
+// invitationColumns is the column list matching scanInvitation.
+const invitationColumns = `id, org_id, email, role, token_hash, invited_by, expires_at, created_at`
+
+// OrganizationRepository implements the ports.OrganizationRepository interface for Postgres (Supabase).
+type OrganizationRepository struct {
+	DB *sql.DB
+}
+
+// NewOrganizationRepository creates a new instance of the OrganizationRepository.
+func NewOrganizationRepository(db *sql.DB) ports.OrganizationRepository {
+	return &OrganizationRepository{DB: db}
+}
+
+// CreateOrganization inserts the organization and its first owner in one transaction,
+// so an organization never exists without an owner.
+func (r *OrganizationRepository) CreateOrganization(ctx context.Context, org domain.Organization, ownerID uuid.UUID) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO organizations (id, name, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.ExecContext(ctx, query, org.ID, org.Name, org.CreatedBy, org.CreatedAt, org.UpdatedAt); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO organization_members (org_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.ExecContext(ctx, query, org.ID, ownerID, domain.OrgRoleOwner, org.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetOrganization retrieves an organization by ID.
+func (r *OrganizationRepository) GetOrganization(ctx context.Context, orgID uuid.UUID) (*domain.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations o WHERE o.id = $1`
+	org, err := scanOrganization(r.DB.QueryRowContext(ctx, query, orgID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Organization not found
+	}
+	if err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// ListUserOrganizations joins the user's memberships with their organizations.
+func (r *OrganizationRepository) ListUserOrganizations(ctx context.Context, userID uuid.UUID) ([]domain.OrganizationMembership, error) {
+	query := `
+		SELECT ` + organizationColumns + `, m.role
+		FROM organization_members m
+		JOIN organizations o ON o.id = m.org_id
+		WHERE m.user_id = $1
+		ORDER BY o.name, o.id
+	`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	memberships := []domain.OrganizationMembership{}
+	for rows.Next() {
+		var membership domain.OrganizationMembership
+		var createdBy uuid.NullUUID
+		err := rows.Scan(
+			&membership.ID,
+			&membership.Name,
+			&createdBy,
+			&membership.CreatedAt,
+			&membership.UpdatedAt,
+			&membership.Role,
+		)
+		if err != nil {
+			return nil, err
+		}
+		membership.CreatedBy = createdBy.UUID
+		memberships = append(memberships, membership)
+	}
+	return memberships, rows.Err()
+}
+
+// RenameOrganization updates the name and updated_at.
+func (r *OrganizationRepository) RenameOrganization(ctx context.Context, orgID uuid.UUID, name string) (bool, error) {
+	query := `UPDATE organizations SET name = $1, updated_at = $2 WHERE id = $3`
+	return execAffected(ctx, r.DB, query, name, time.Now(), orgID)
+}
+
+// DeleteOrganization deletes the organization; members and invitations are removed by ON DELETE CASCADE.
+func (r *OrganizationRepository) DeleteOrganization(ctx context.Context, orgID uuid.UUID) (bool, error) {
+	return execAffected(ctx, r.DB, `DELETE FROM organizations WHERE id = $1`, orgID)
+}
+
+// --- Members ---
+
+// GetMemberRole looks up the user's role in the organization.
+func (r *OrganizationRepository) GetMemberRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (string, error) {
+	query := `SELECT role FROM organization_members WHERE org_id = $1 AND user_id = $2`
+	var role string
+	err := r.DB.QueryRowContext(ctx, query, orgID, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil // Not a member
+	}
+	if err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+// ListMembers joins the members with their user accounts.
+func (r *OrganizationRepository) ListMembers(ctx context.Context, orgID uuid.UUID) ([]domain.OrganizationMember, error) {
+	query := `
+		SELECT m.org_id, m.user_id, u.name, u.email, m.role, m.created_at
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.org_id = $1
+		ORDER BY CASE m.role WHEN 'owner' THEN 0 WHEN 'admin' THEN 1 ELSE 2 END, u.name, u.id
+	`
+	rows, err := r.DB.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []domain.OrganizationMember{}
+	for rows.Next() {
+		var member domain.OrganizationMember
+		err := rows.Scan(&member.OrgID, &member.UserID, &member.Name, &member.Email, &member.Role, &member.JoinedAt)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// CountOwners counts the members with the owner role.
+func (r *OrganizationRepository) CountOwners(ctx context.Context, orgID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM organization_members WHERE org_id = $1 AND role = $2`
+	var count int
+	err := r.DB.QueryRowContext(ctx, query, orgID, domain.OrgRoleOwner).Scan(&count)
+	return count, err
+}
+
+// UpdateMemberRole sets the role of an existing member. Taking the owner role away happens in one
+// transaction with the last-owner check, so two owners cannot demote each other at the same time.
+func (r *OrganizationRepository) UpdateMemberRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, role string) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if role != domain.OrgRoleOwner {
+		if err := requireOtherOwner(ctx, tx, orgID, userID); err != nil {
+			return false, err
+		}
+	}
+
+	query := `UPDATE organization_members SET role = $1 WHERE org_id = $2 AND user_id = $3`
+	found, err := execAffected(ctx, tx, query, role, orgID, userID)
+	if err != nil || !found {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// RemoveMember deletes the membership row, in one transaction with the last-owner check.
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := requireOtherOwner(ctx, tx, orgID, userID); err != nil {
+		return false, err
+	}
+
+	query := `DELETE FROM organization_members WHERE org_id = $1 AND user_id = $2`
+	found, err := execAffected(ctx, tx, query, orgID, userID)
+	if err != nil || !found {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// requireOtherOwner locks the owner rows of the organization until the transaction ends and fails with
+// domain.ErrLastOrgOwner if the user is the only owner. A concurrent transaction waits for the lock and
+// then no longer sees an owner demoted or removed in the meantime.
+func requireOtherOwner(ctx context.Context, tx *sql.Tx, orgID uuid.UUID, userID uuid.UUID) error {
+	query := `
+		SELECT user_id FROM organization_members
+		WHERE org_id = $1 AND role = $2
+		ORDER BY user_id
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, query, orgID, domain.OrgRoleOwner)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	owners, isOwner := 0, false
+	for rows.Next() {
+		var ownerID uuid.UUID
+		if err := rows.Scan(&ownerID); err != nil {
+			return err
+		}
+		owners++
+		isOwner = isOwner || ownerID == userID
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if isOwner && owners <= 1 {
+		return domain.ErrLastOrgOwner
+	}
+	return nil
+}
+
+// --- Invitations ---
+
+// SaveInvitation inserts the invitation; re-inviting the same address replaces the token, role and expiry,
+// so only the newest invitation email works.
+func (r *OrganizationRepository) SaveInvitation(ctx context.Context, invitation domain.OrganizationInvitation) error {
+	query := `
+		INSERT INTO organization_invitations (id, org_id, email, role, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (org_id, email) DO UPDATE
+		SET id = EXCLUDED.id, role = EXCLUDED.role, token_hash = EXCLUDED.token_hash,
+		    invited_by = EXCLUDED.invited_by, expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+	`
+	_, err := r.DB.ExecContext(
+		ctx,
+		query,
+		invitation.ID,
+		invitation.OrgID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.ExpiresAt,
+		invitation.CreatedAt,
+	)
+	return err
+}
+
+// GetInvitationByTokenHash retrieves an invitation by the SHA-256 hash of its token.
+func (r *OrganizationRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*domain.OrganizationInvitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM organization_invitations WHERE token_hash = $1`
+	invitation, err := scanInvitation(r.DB.QueryRowContext(ctx, query, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Invitation not found
+	}
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ListInvitations returns the organization's invitations, newest first.
+func (r *OrganizationRepository) ListInvitations(ctx context.Context, orgID uuid.UUID) ([]domain.OrganizationInvitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM organization_invitations
+		WHERE org_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.DB.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []domain.OrganizationInvitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *invitation)
+	}
+	return invitations, rows.Err()
+}
+
+// DeleteInvitation deletes one invitation of the organization.
+func (r *OrganizationRepository) DeleteInvitation(ctx context.Context, orgID uuid.UUID, invitationID uuid.UUID) (bool, error) {
+	query := `DELETE FROM organization_invitations WHERE id = $1 AND org_id = $2`
+	return execAffected(ctx, r.DB, query, invitationID, orgID)
+}
+
+// AcceptInvitation consumes the invitation and inserts the membership in one transaction. The
+// invitation is deleted by ID and token hash, so a replaced or concurrently used invitation fails.
+// A user who is already a member keeps their current role.
+func (r *OrganizationRepository) AcceptInvitation(ctx context.Context, invitation domain.OrganizationInvitation, userID uuid.UUID) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM organization_invitations WHERE id = $1 AND token_hash = $2`
+	found, err := execAffected(ctx, tx, query, invitation.ID, invitation.TokenHash)
+	if err != nil || !found {
+		return false, err
+	}
+
+	query = `
+		INSERT INTO organization_members (org_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (org_id, user_id) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, invitation.OrgID, userID, invitation.Role, time.Now()); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// execAffected runs a statement and reports whether it changed at least one row.
+func execAffected(ctx context.Context, db execer, query string, args ...any) (bool, error) {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// scanOrganization reads one row selected with organizationColumns.
+func scanOrganization(row interface{ Scan(dest ...any) error }) (*domain.Organization, error) {
+	org := &domain.Organization{}
+	var createdBy uuid.NullUUID
+	if err := row.Scan(&org.ID, &org.Name, &createdBy, &org.CreatedAt, &org.UpdatedAt); err != nil {
+		return nil, err
+	}
+	org.CreatedBy = createdBy.UUID
+	return org, nil
+}
+
+// scanInvitation reads one row selected with invitationColumns.
+func scanInvitation(row interface{ Scan(dest ...any) error }) (*domain.OrganizationInvitation, error) {
+	invitation := &domain.OrganizationInvitation{}
+	var invitedBy uuid.NullUUID
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.OrgID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.TokenHash,
+		&invitedBy,
+		&invitation.ExpiresAt,
+		&invitation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	invitation.InvitedBy = invitedBy.UUID
+	return invitation, nil
+}
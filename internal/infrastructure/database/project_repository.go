@@ -0,0 +1,395 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// projectColumns is the column list matching scanProject.
+const projectColumns = `p.id, p.key, p.name, p.description, p.owner_id, p.start_date, p.end_date, p.status, p.archived_at, p.created_at, p.updated_at`
+
+// ProjectRepository implements the ports.ProjectRepository interface for Postgres (Supabase).
+type ProjectRepository struct {
+	DB *sql.DB
+}
+
+// NewProjectRepository creates a new instance of the ProjectRepository.
+func NewProjectRepository(db *sql.DB) ports.ProjectRepository {
+	return &ProjectRepository{DB: db}
+}
+
+// CreateProject inserts the project and its owner's admin membership in one transaction.
+func (r *ProjectRepository) CreateProject(ctx context.Context, project domain.Project) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO projects (id, key, name, description, owner_id, start_date, end_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		project.ID,
+		project.Key,
+		project.Name,
+		project.Description,
+		project.OwnerID,
+		dateArg(project.StartDate),
+		dateArg(project.EndDate),
+		project.Status,
+		project.CreatedAt,
+		project.UpdatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return errors.New("project key already in use")
+		}
+		return err
+	}
+
+	query = `
+		INSERT INTO project_members (project_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.ExecContext(ctx, query, project.ID, project.OwnerID, domain.ProjectRoleAdmin, project.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetProject retrieves a project by ID.
+func (r *ProjectRepository) GetProject(ctx context.Context, projectID uuid.UUID) (*domain.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects p WHERE p.id = $1`
+	return scanProjectRow(r.DB.QueryRowContext(ctx, query, projectID))
+}
+
+// GetProjectByKey retrieves a project by its key.
+func (r *ProjectRepository) GetProjectByKey(ctx context.Context, key string) (*domain.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects p WHERE p.key = $1`
+	return scanProjectRow(r.DB.QueryRowContext(ctx, query, key))
+}
+
+// ListProjects builds the WHERE clause from the filter and runs a count and a page query.
+func (r *ProjectRepository) ListProjects(ctx context.Context, filter domain.ProjectFilter) ([]domain.ProjectMembership, int, error) {
+	conditions := []string{"m.user_id = $1"}
+	args := []any{filter.UserID}
+	addArg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("p.status = %s", addArg(filter.Status)))
+	}
+	if !filter.IncludeArchived {
+		conditions = append(conditions, "p.archived_at IS NULL")
+	}
+	from := ` FROM project_members m JOIN projects p ON p.id = m.project_id WHERE ` + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*)`+from, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + projectColumns + `, m.role` + from +
+		fmt.Sprintf(` ORDER BY p.key LIMIT %s OFFSET %s`, addArg(filter.Limit), addArg(filter.Offset))
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	projects := []domain.ProjectMembership{}
+	for rows.Next() {
+		var role string
+		project, err := scanProject(rows, &role)
+		if err != nil {
+			return nil, 0, err
+		}
+		projects = append(projects, domain.ProjectMembership{Project: *project, Role: role})
+	}
+	return projects, total, rows.Err()
+}
+
+// UpdateProject saves the editable fields and updated_at.
+func (r *ProjectRepository) UpdateProject(ctx context.Context, project domain.Project) (bool, error) {
+	query := `
+		UPDATE projects
+		SET name = $1, description = $2, start_date = $3, end_date = $4, status = $5, updated_at = $6
+		WHERE id = $7
+	`
+	return execAffected(
+		ctx,
+		r.DB,
+		query,
+		project.Name,
+		project.Description,
+		dateArg(project.StartDate),
+		dateArg(project.EndDate),
+		project.Status,
+		time.Now(),
+		project.ID,
+	)
+}
+
+// SetProjectArchived sets or clears archived_at. Archiving an archived project keeps the original time.
+func (r *ProjectRepository) SetProjectArchived(ctx context.Context, projectID uuid.UUID, archived bool) (bool, error) {
+	query := `UPDATE projects SET archived_at = COALESCE(archived_at, $1), updated_at = $1 WHERE id = $2`
+	if !archived {
+		query = `UPDATE projects SET archived_at = NULL, updated_at = $1 WHERE id = $2`
+	}
+	return execAffected(ctx, r.DB, query, time.Now(), projectID)
+}
+
+// DeleteProject deletes the project; its members are removed by ON DELETE CASCADE.
+func (r *ProjectRepository) DeleteProject(ctx context.Context, projectID uuid.UUID) (bool, error) {
+	return execAffected(ctx, r.DB, `DELETE FROM projects WHERE id = $1`, projectID)
+}
+
+// TransferOwnedProjects hands the user's projects to another admin in one transaction, unless one of them has none.
+func (r *ProjectRepository) TransferOwnedProjects(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the owned projects, so no admin can be removed from them in the meantime
+	query := `
+		SELECT p.key
+		FROM projects p
+		WHERE p.owner_id = $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM project_members m
+			WHERE m.project_id = p.id AND m.role = $2 AND m.user_id <> $1
+		  )
+		ORDER BY p.key
+	`
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM projects WHERE owner_id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, query, userID, domain.ProjectRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocked := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		blocked = append(blocked, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(blocked) > 0 {
+		return blocked, nil
+	}
+
+	query = `
+		UPDATE projects p
+		SET owner_id = (
+			SELECT m.user_id FROM project_members m
+			WHERE m.project_id = p.id AND m.role = $2 AND m.user_id <> $1
+			ORDER BY m.created_at, m.user_id
+			LIMIT 1
+		), updated_at = $3
+		WHERE p.owner_id = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, userID, domain.ProjectRoleAdmin, time.Now()); err != nil {
+		return nil, err
+	}
+	return nil, tx.Commit()
+}
+
+// --- Members ---
+
+// GetMemberRole looks up the user's role in the project.
+func (r *ProjectRepository) GetMemberRole(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (string, error) {
+	query := `SELECT role FROM project_members WHERE project_id = $1 AND user_id = $2`
+	var role string
+	err := r.DB.QueryRowContext(ctx, query, projectID, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil // Not a member
+	}
+	if err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+// ListMembers joins the members with their user accounts.
+func (r *ProjectRepository) ListMembers(ctx context.Context, projectID uuid.UUID) ([]domain.ProjectMember, error) {
+	query := `
+		SELECT m.project_id, m.user_id, u.name, u.email, m.role, m.created_at
+		FROM project_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.project_id = $1
+		ORDER BY CASE m.role WHEN 'admin' THEN 0 WHEN 'editor' THEN 1 ELSE 2 END, u.name, u.id
+	`
+	rows, err := r.DB.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []domain.ProjectMember{}
+	for rows.Next() {
+		var member domain.ProjectMember
+		err := rows.Scan(&member.ProjectID, &member.UserID, &member.Name, &member.Email, &member.Role, &member.AddedAt)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// AddMember inserts the membership, ignoring users who already are members.
+func (r *ProjectRepository) AddMember(ctx context.Context, projectID uuid.UUID, userID uuid.UUID, role string) (bool, error) {
+	query := `
+		INSERT INTO project_members (project_id, user_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id, user_id) DO NOTHING
+	`
+	return execAffected(ctx, r.DB, query, projectID, userID, role, time.Now())
+}
+
+// UpdateMemberRole sets the role of an existing member. Taking the admin role away happens in one
+// transaction with the last-admin check, so two admins cannot demote each other at the same time.
+func (r *ProjectRepository) UpdateMemberRole(ctx context.Context, projectID uuid.UUID, userID uuid.UUID, role string) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if role != domain.ProjectRoleAdmin {
+		if err := requireOtherAdmin(ctx, tx, projectID, userID); err != nil {
+			return false, err
+		}
+	}
+
+	query := `UPDATE project_members SET role = $1 WHERE project_id = $2 AND user_id = $3`
+	found, err := execAffected(ctx, tx, query, role, projectID, userID)
+	if err != nil || !found {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// RemoveMember deletes the membership row, in one transaction with the last-admin check.
+func (r *ProjectRepository) RemoveMember(ctx context.Context, projectID uuid.UUID, userID uuid.UUID) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := requireOtherAdmin(ctx, tx, projectID, userID); err != nil {
+		return false, err
+	}
+
+	query := `DELETE FROM project_members WHERE project_id = $1 AND user_id = $2`
+	found, err := execAffected(ctx, tx, query, projectID, userID)
+	if err != nil || !found {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// requireOtherAdmin locks the project row until the transaction ends and fails with
+// domain.ErrLastProjectAdmin if the user is the only admin. Every change that takes the admin role
+// away, and TransferOwnedProjects, takes the same lock, so they run one after another.
+func requireOtherAdmin(ctx context.Context, tx *sql.Tx, projectID uuid.UUID, userID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM projects WHERE id = $1 FOR UPDATE`, projectID); err != nil {
+		return err
+	}
+
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id = $3)
+		FROM project_members
+		WHERE project_id = $1 AND role = $2
+	`
+	var admins, isAdmin int
+	if err := tx.QueryRowContext(ctx, query, projectID, domain.ProjectRoleAdmin, userID).Scan(&admins, &isAdmin); err != nil {
+		return err
+	}
+	if isAdmin > 0 && admins <= 1 {
+		return domain.ErrLastProjectAdmin
+	}
+	return nil
+}
+
+// dateArg passes an optional date as "YYYY-MM-DD", so the session time zone cannot shift it to another day.
+func dateArg(date *time.Time) any {
+	if date == nil {
+		return nil
+	}
+	return date.Format(domain.ProjectDateLayout)
+}
+
+// scanProjectRow reads a single project row. Returns nil, nil if there is no row.
+func scanProjectRow(row *sql.Row) (*domain.Project, error) {
+	project, err := scanProject(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Project not found
+	}
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// scanProject reads the projectColumns of a *sql.Row or *sql.Rows, followed by any extra columns.
+func scanProject(row interface{ Scan(dest ...any) error }, extra ...any) (*domain.Project, error) {
+	project := &domain.Project{}
+	var ownerID uuid.NullUUID
+	var startDate, endDate, archivedAt sql.NullTime
+	dest := []any{
+		&project.ID,
+		&project.Key,
+		&project.Name,
+		&project.Description,
+		&ownerID,
+		&startDate,
+		&endDate,
+		&project.Status,
+		&archivedAt,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	project.OwnerID = ownerID.UUID
+	if startDate.Valid {
+		project.StartDate = &startDate.Time
+	}
+	if endDate.Valid {
+		project.EndDate = &endDate.Time
+	}
+	if archivedAt.Valid {
+		project.ArchivedAt = &archivedAt.Time
+	}
+	return project, nil
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// RevocationStore implements the ports.RevocationStore interface for Postgres (Supabase).
+type RevocationStore struct {
+	DB *sql.DB
+}
+
+// NewRevocationStore creates a new instance of the RevocationStore.
+func NewRevocationStore(db *sql.DB) ports.RevocationStore {
+	return &RevocationStore{DB: db}
+}
+
+// RevokeToken records a single revoked access token in the 'revoked_tokens' table.
+func (s *RevocationStore) RevokeToken(ctx context.Context, tokenID string, userID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	_, err := s.DB.ExecContext(ctx, query, tokenID, userID, expiresAt)
+	return err
+}
+
+// ConsumeToken records the token in 'revoked_tokens'; the insert only succeeds for the first use,
+// so concurrent requests with the same token cannot both consume it.
+func (s *RevocationStore) ConsumeToken(ctx context.Context, tokenID string, userID uuid.UUID, expiresAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	return execAffected(ctx, s.DB, query, tokenID, userID, expiresAt)
+}
+
+// RevokeAllForUser revokes every token of the user issued before issuedBefore, or in the same second.
+// The cut-off is stored in whole seconds like the iat claim it is compared with (see IsRevoked).
+// A later call always moves the cut-off forward, never backward.
+func (s *RevocationStore) RevokeAllForUser(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, expiresAt time.Time) error {
+	query := `
+		INSERT INTO user_token_revocations (user_id, revoked_before, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before),
+		    expires_at = GREATEST(user_token_revocations.expires_at, EXCLUDED.expires_at)
+	`
+	_, err := s.DB.ExecContext(ctx, query, userID, issuedBefore.Truncate(time.Second), expiresAt)
+	return err
+}
+
+// IsRevoked checks the per-token and per-user revocation tables, the token's session and
+// the account status in a single query. A token issued in the second of a user-wide cut-off
+// may predate it, so it is revoked as well.
+func (s *RevocationStore) IsRevoked(ctx context.Context, tokenID string, userID uuid.UUID, sessionID uuid.UUID, issuedAt time.Time) (bool, error) {
+	query := `
+		SELECT
+			EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)
+			OR EXISTS (SELECT 1 FROM user_token_revocations WHERE user_id = $2 AND revoked_before >= $3)
+			OR EXISTS (SELECT 1 FROM sessions WHERE id = $4 AND revoked_at IS NOT NULL)
+			OR NOT EXISTS (SELECT 1 FROM users WHERE id = $2 AND disabled_at IS NULL)
+	`
+	var revoked bool
+	err := s.DB.QueryRowContext(ctx, query, tokenID, userID, issuedAt, sessionID).Scan(&revoked)
+	if err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
+
+// PruneExpired removes revocation entries for tokens that have expired anyway.
+func (s *RevocationStore) PruneExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+
+	result, err := s.DB.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at < $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	tokens, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err = s.DB.ExecContext(ctx, `DELETE FROM user_token_revocations WHERE expires_at < $1`, now)
+	if err != nil {
+		return tokens, err
+	}
+	users, err := result.RowsAffected()
+	if err != nil {
+		return tokens, err
+	}
+
+	return tokens + users, nil
+}
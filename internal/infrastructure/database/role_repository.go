@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// RoleRepository implements the ports.RoleRepository interface for Postgres (Supabase).
+type RoleRepository struct {
+	DB *sql.DB
+}
+
+// NewRoleRepository creates a new instance of the RoleRepository.
+func NewRoleRepository(db *sql.DB) ports.RoleRepository {
+	return &RoleRepository{DB: db}
+}
+
+// ListRoles returns every role with its permissions, ordered by name.
+func (r *RoleRepository) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	query := `
+		SELECT r.name, r.description, r.is_system,
+		       COALESCE(ARRAY_AGG(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		GROUP BY r.name
+		ORDER BY r.name
+	`
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []domain.Role{}
+	for rows.Next() {
+		var role domain.Role
+		if err := rows.Scan(&role.Name, &role.Description, &role.IsSystem, pq.Array(&role.Permissions)); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// GetUserRoles returns the names of the roles granted to the user, ordered by name.
+func (r *RoleRepository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	query := `SELECT role FROM user_roles WHERE user_id = $1 ORDER BY role`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []string{}
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// AssignRole inserts the user/role pair, ignoring duplicates.
+func (r *RoleRepository) AssignRole(ctx context.Context, userID uuid.UUID, role string) error {
+	query := `
+		INSERT INTO user_roles (user_id, role)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, role) DO NOTHING
+	`
+	_, err := r.DB.ExecContext(ctx, query, userID, role)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" { // foreign_key_violation
+		if pqErr.Constraint == "user_roles_role_fkey" {
+			return errors.New("role does not exist")
+		}
+		return errors.New("user not found")
+	}
+	return err
+}
+
+// RemoveRole deletes the user/role pair.
+func (r *RoleRepository) RemoveRole(ctx context.Context, userID uuid.UUID, role string) (bool, error) {
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND role = $2`
+	return execAffected(ctx, r.DB, query, userID, role)
+}
+
+// HasPermission checks the role_permissions table for any of the roles in a single query.
+func (r *RoleRepository) HasPermission(ctx context.Context, roles []string, permission string) (bool, error) {
+	if len(roles) == 0 {
+		return false, nil
+	}
+
+	query := `SELECT EXISTS (SELECT 1 FROM role_permissions WHERE role = ANY($1) AND permission = $2)`
+	var allowed bool
+	err := r.DB.QueryRowContext(ctx, query, pq.Array(roles), permission).Scan(&allowed)
+	if err != nil {
+		return false, err
+	}
+	return allowed, nil
+}
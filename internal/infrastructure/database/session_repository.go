@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// SessionRepository implements the ports.SessionRepository interface for Postgres (Supabase).
+type SessionRepository struct {
+	DB *sql.DB
+}
+
+// NewSessionRepository creates a new instance of the SessionRepository.
+func NewSessionRepository(db *sql.DB) ports.SessionRepository {
+	return &SessionRepository{DB: db}
+}
+
+// CreateSession saves a new session record to the 'sessions' table.
+func (r *SessionRepository) CreateSession(ctx context.Context, session domain.Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, ip, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.DB.ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.UserID,
+		session.UserAgent,
+		session.IP,
+		session.CreatedAt,
+		session.LastSeenAt,
+	)
+	return err
+}
+
+// ListActiveSessions returns the user's sessions that have not been revoked.
+func (r *SessionRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	query := `
+		SELECT id, user_id, user_agent, ip, created_at, last_seen_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY last_seen_at DESC
+	`
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain.Session{}
+	for rows.Next() {
+		var session domain.Session
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.UserAgent,
+			&session.IP,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// RevokeSession revokes the session and its refresh tokens in one transaction.
+func (r *SessionRepository) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (bool, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	query := `
+		UPDATE sessions SET revoked_at = $1
+		WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL
+	`
+	result, err := tx.ExecContext(ctx, query, now, sessionID, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	tokensQuery := `UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+	if _, err := tx.ExecContext(ctx, tokensQuery, now, sessionID); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// RevokeAllSessions revokes every session of the user except keepSessionID, along with their refresh tokens.
+func (r *SessionRepository) RevokeAllSessions(ctx context.Context, userID uuid.UUID, keepSessionID uuid.UUID) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	query := `
+		UPDATE sessions SET revoked_at = $1
+		WHERE user_id = $2 AND id <> $3 AND revoked_at IS NULL
+	`
+	if _, err := tx.ExecContext(ctx, query, now, userID, keepSessionID); err != nil {
+		return err
+	}
+
+	tokensQuery := `
+		UPDATE refresh_tokens SET revoked_at = $1
+		WHERE user_id = $2 AND family_id <> $3 AND revoked_at IS NULL
+	`
+	if _, err := tx.ExecContext(ctx, tokensQuery, now, userID, keepSessionID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// TouchSession updates last_seen_at at most once per minInterval to avoid a write on every request.
+func (r *SessionRepository) TouchSession(ctx context.Context, sessionID uuid.UUID, minInterval time.Duration) error {
+	now := time.Now()
+	query := `
+		UPDATE sessions SET last_seen_at = $1
+		WHERE id = $2 AND last_seen_at < $3 AND revoked_at IS NULL
+	`
+	_, err := r.DB.ExecContext(ctx, query, now, sessionID, now.Add(-minInterval))
+	return err
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// TokenRepository implements the ports.TokenRepository interface for Postgres (Supabase).
+type TokenRepository struct {
+	DB *sql.DB
+}
+
+// NewTokenRepository creates a new instance of the TokenRepository.
+func NewTokenRepository(db *sql.DB) ports.TokenRepository {
+	return &TokenRepository{DB: db}
+}
+
+// CreateRefreshToken saves a new refresh token record to the 'refresh_tokens' table.
+func (r *TokenRepository) CreateRefreshToken(ctx context.Context, token domain.RefreshToken) error {
+	return insertRefreshToken(ctx, r.DB, token)
+}
+
+// GetRefreshTokenByHash retrieves a refresh token by its SHA-256 hash.
+func (r *TokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at, used_at, revoked_at
+		FROM refresh_tokens WHERE token_hash = $1
+	`
+	token := &domain.RefreshToken{}
+	var usedAt, revokedAt sql.NullTime
+	err := r.DB.QueryRowContext(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&usedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Token not found
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return token, nil
+}
+
+// RotateRefreshToken marks the old token as used and stores its replacement atomically.
+// The conditional UPDATE guarantees that two concurrent refreshes cannot both succeed.
+func (r *TokenRepository) RotateRefreshToken(ctx context.Context, oldTokenID uuid.UUID, next domain.RefreshToken) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE refresh_tokens SET used_at = $1, replaced_by = $2
+		WHERE id = $3 AND used_at IS NULL AND revoked_at IS NULL
+	`
+	result, err := tx.ExecContext(ctx, query, time.Now(), next.ID, oldTokenID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		// Someone else already used (or revoked) this token
+		return domain.ErrRefreshTokenReused
+	}
+
+	if err := insertRefreshToken(ctx, tx, next); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeRefreshTokenFamily revokes all still-active tokens in a family.
+func (r *TokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens SET revoked_at = $1
+		WHERE family_id = $2 AND revoked_at IS NULL
+	`
+	_, err := r.DB.ExecContext(ctx, query, time.Now(), familyID)
+	return err
+}
+
+// RevokeAllRefreshTokens revokes every active refresh token of a user (logout from all devices).
+func (r *TokenRepository) RevokeAllRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens SET revoked_at = $1
+		WHERE user_id = $2 AND revoked_at IS NULL
+	`
+	_, err := r.DB.ExecContext(ctx, query, time.Now(), userID)
+	return err
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertRefreshToken writes a refresh token row using either a plain connection or a transaction.
+func insertRefreshToken(ctx context.Context, db execer, token domain.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := db.ExecContext(
+		ctx,
+		query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
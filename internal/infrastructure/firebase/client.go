@@ -2,38 +2,162 @@ package firebase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"os"
 
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/auth"
+	"github.com/mitcheltastic/ManproBackend/config"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
 	"google.golang.org/api/option"
 )
 
+// EmulatorHostEnv is read by the Firebase Admin SDK itself: when set, all Auth calls go to the
+// emulator and ID tokens are accepted without signature checks.
+const EmulatorHostEnv = "FIREBASE_AUTH_EMULATOR_HOST"
+
+// Auth is what the backend needs from Firebase. It is implemented by Client (the real SDK or the
+// emulator) and by FakeVerifier (locally signed test tokens).
+type Auth interface {
+	ports.FirebaseTokenVerifier
+	ports.FirebaseClaimsUpdater
+	ports.TokenVerifier
+}
+
+// Client and FakeVerifier implement Auth.
+var (
+	_ Auth = (*Client)(nil)
+	_ Auth = (*FakeVerifier)(nil)
+)
+
+// AuthClient is the part of the Admin SDK's *auth.Client used by Client, so a fake can stand in for it.
+type AuthClient interface {
+	VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error)
+	SetCustomUserClaims(ctx context.Context, uid string, customClaims map[string]interface{}) error
+}
+
 // Client holds the initialized Firebase Auth client.
 type Client struct {
-	AuthClient *auth.Client
+	AuthClient AuthClient
 }
 
-// NewClient initializes the Firebase Admin SDK using the service account file.
-func NewClient(serviceAccountKeyPath string) *Client {
-	ctx := context.Background()
+// NewAuth selects the Firebase implementation configured by FIREBASE_VERIFIER:
+// the Admin SDK (or the Auth emulator), or the fake verifier for offline development and tests.
+func NewAuth(ctx context.Context, cfg *config.Config) (Auth, error) {
+	if cfg.FirebaseVerifier == "fake" {
+		log.Println("WARNING: Using the fake Firebase verifier; Firebase ID tokens are NOT checked against Firebase.")
+		return NewFakeVerifier([]byte(cfg.FirebaseFakeSecret), cfg.FirebaseProjectID)
+	}
+	return NewClient(ctx, cfg.FirebaseServiceKeyPath, cfg.FirebaseProjectID)
+}
 
+// NewClient initializes the Firebase Admin SDK. serviceAccountKeyPath is optional: without it the
+// Application Default Credentials are used. If FIREBASE_AUTH_EMULATOR_HOST is set, the SDK talks to
+// the Auth emulator instead, which needs no credentials but a projectID.
+func NewClient(ctx context.Context, serviceAccountKeyPath string, projectID string) (*Client, error) {
 	// 1. Create options using the path to the service account JSON
-	opt := option.WithCredentialsFile(serviceAccountKeyPath)
+	var opts []option.ClientOption
+	if serviceAccountKeyPath != "" {
+		opts = append(opts, option.WithCredentialsFile(serviceAccountKeyPath))
+	}
+	if emulatorHost := os.Getenv(EmulatorHostEnv); emulatorHost != "" {
+		if projectID == "" {
+			return nil, errors.New("a Firebase project ID is required with the Auth emulator")
+		}
+		log.Printf("Using the Firebase Auth emulator at %s", emulatorHost)
+	}
 
 	// 2. Initialize the Firebase App
-	app, err := firebase.NewApp(ctx, nil, opt)
+	var fbConfig *firebase.Config
+	if projectID != "" {
+		fbConfig = &firebase.Config{ProjectID: projectID}
+	}
+	app, err := firebase.NewApp(ctx, fbConfig, opts...)
 	if err != nil {
-		log.Fatalf("Error initializing Firebase app: %v", err)
+		return nil, fmt.Errorf("error initializing Firebase app: %w", err)
 	}
 
 	// 3. Get the Authentication client instance
 	authClient, err := app.Auth(ctx)
 	if err != nil {
-		log.Fatalf("Error getting Firebase Auth client: %v", err)
+		return nil, fmt.Errorf("error getting Firebase Auth client: %w", err)
 	}
 
 	return &Client{
 		AuthClient: authClient,
+	}, nil
+}
+
+// VerifyIDToken verifies a Firebase ID token and extracts the identity fields used by the backend.
+func (c *Client) VerifyIDToken(ctx context.Context, idToken string) (*domain.FirebaseIdentity, error) {
+	token, err := c.AuthClient.VerifyIDToken(ctx, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &domain.FirebaseIdentity{
+		UID:            token.UID,
+		SignInProvider: token.Firebase.SignInProvider,
+	}
+	if email, ok := token.Claims["email"].(string); ok {
+		identity.Email = email
+	}
+	if verified, ok := token.Claims["email_verified"].(bool); ok {
+		identity.EmailVerified = verified
+	}
+	if name, ok := token.Claims["name"].(string); ok {
+		identity.Name = name
+	}
+	identity.Roles = RolesFromClaims(token.Claims)
+
+	return identity, nil
+}
+
+// VerifyToken verifies a Firebase ID token for the chained auth middleware.
+// Every verification failure is reported as domain.ErrInvalidToken, so the next verifier can be tried.
+func (c *Client) VerifyToken(ctx context.Context, token string) (*domain.Principal, error) {
+	identity, err := c.VerifyIDToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidToken, err)
+	}
+
+	return principalFromIdentity(identity), nil
+}
+
+// principalFromIdentity converts a verified Firebase identity into a Principal.
+func principalFromIdentity(identity *domain.FirebaseIdentity) *domain.Principal {
+	return &domain.Principal{
+		UserID:        identity.UID,
+		Email:         identity.Email,
+		EmailVerified: identity.EmailVerified,
+		Name:          identity.Name,
+		Roles:         identity.Roles,
+		Provider:      domain.PrincipalProviderFirebase,
+		FirebaseUID:   identity.UID,
+	}
+}
+
+// SetCustomClaims replaces the custom claims of the Firebase user.
+func (c *Client) SetCustomClaims(ctx context.Context, uid string, claims map[string]interface{}) error {
+	return c.AuthClient.SetCustomUserClaims(ctx, uid, claims)
+}
+
+// RolesFromClaims reads the roles custom claim of a verified Firebase token.
+// Custom claims are decoded from JSON, so the list arrives as []interface{}.
+func RolesFromClaims(claims map[string]interface{}) []string {
+	values, ok := claims[domain.FirebaseRolesClaim].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	roles := make([]string, 0, len(values))
+	for _, value := range values {
+		if role, ok := value.(string); ok {
+			roles = append(roles, role)
+		}
 	}
+	return roles
 }
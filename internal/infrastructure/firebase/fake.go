@@ -0,0 +1,137 @@
+package firebase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+)
+
+// FakeIssuer is the issuer of the test tokens accepted by FakeVerifier.
+const FakeIssuer = "manpro-fake-firebase"
+
+// FakeProjectID is the audience of the test tokens when no Firebase project ID is configured.
+const FakeProjectID = "manpro-fake-project"
+
+// FakeTokenClaims mirror the claims of a Firebase ID token that the backend reads.
+type FakeTokenClaims struct {
+	Email         string   `json:"email,omitempty"`
+	EmailVerified bool     `json:"email_verified"`
+	Name          string   `json:"name,omitempty"`
+	Roles         []string `json:"roles,omitempty"`
+	Firebase      struct {
+		SignInProvider string `json:"sign_in_provider"`
+	} `json:"firebase"`
+	jwt.RegisteredClaims
+}
+
+// FakeVerifier stands in for Firebase in local development and automated tests: it accepts
+// HS256 tokens signed with a shared secret (see SignFakeToken) instead of real ID tokens,
+// and keeps custom claims in memory. Like a Firebase ID token, a test token is only accepted
+// for the project it was issued for (its audience). It must never be used in production.
+type FakeVerifier struct {
+	secret    []byte
+	projectID string
+
+	mu           sync.Mutex
+	customClaims map[string]map[string]interface{}
+}
+
+// NewFakeVerifier creates a FakeVerifier for tokens signed with the secret for the project
+// (FakeProjectID if projectID is empty).
+func NewFakeVerifier(secret []byte, projectID string) (*FakeVerifier, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("the fake Firebase verifier needs a secret")
+	}
+	return &FakeVerifier{
+		secret:       secret,
+		projectID:    fakeProjectID(projectID),
+		customClaims: map[string]map[string]interface{}{},
+	}, nil
+}
+
+// SignFakeToken creates a test token for the user with the given UID in the project
+// (FakeProjectID if projectID is empty), valid for ttl.
+func SignFakeToken(secret []byte, projectID string, uid string, claims FakeTokenClaims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	if claims.Firebase.SignInProvider == "" {
+		claims.Firebase.SignInProvider = "password"
+	}
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    FakeIssuer,
+		Subject:   uid,
+		Audience:  jwt.ClaimStrings{fakeProjectID(projectID)},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
+
+// VerifyIDToken checks a test token and extracts the same identity fields as Client.VerifyIDToken.
+func (f *FakeVerifier) VerifyIDToken(ctx context.Context, idToken string) (*domain.FirebaseIdentity, error) {
+	claims := &FakeTokenClaims{}
+	_, err := jwt.ParseWithClaims(
+		idToken,
+		claims,
+		func(*jwt.Token) (interface{}, error) { return f.secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(FakeIssuer),
+		jwt.WithAudience(f.projectID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fake Firebase token: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid fake Firebase token: missing subject")
+	}
+
+	return &domain.FirebaseIdentity{
+		UID:            claims.Subject,
+		Email:          claims.Email,
+		EmailVerified:  claims.EmailVerified,
+		Name:           claims.Name,
+		SignInProvider: claims.Firebase.SignInProvider,
+		Roles:          claims.Roles,
+	}, nil
+}
+
+// fakeProjectID defaults an empty project ID to FakeProjectID.
+func fakeProjectID(projectID string) string {
+	if projectID == "" {
+		return FakeProjectID
+	}
+	return projectID
+}
+
+// VerifyToken verifies a test token for the chained auth middleware.
+func (f *FakeVerifier) VerifyToken(ctx context.Context, token string) (*domain.Principal, error) {
+	identity, err := f.VerifyIDToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidToken, err)
+	}
+	return principalFromIdentity(identity), nil
+}
+
+// SetCustomClaims records the claims in memory; test tokens carry whatever claims they were signed with.
+func (f *FakeVerifier) SetCustomClaims(ctx context.Context, uid string, claims map[string]interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.customClaims[uid] = claims
+	log.Printf("Fake Firebase: custom claims of %s set to %v", uid, claims)
+	return nil
+}
+
+// CustomClaims returns the claims last set for the user (nil if none).
+func (f *FakeVerifier) CustomClaims(uid string) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.customClaims[uid]
+}
@@ -1,57 +1,137 @@
 package router
 
 import (
-	"context"
+	"errors"
+	"log"
 	"net/http"
+	"slices"
 	"strings"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
-	fbclient "github.com/mitcheltastic/ManproBackend/internal/infrastructure/firebase"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+	"github.com/mitcheltastic/ManproBackend/internal/handler"
 )
 
-// ContextKey is used to store and retrieve data from context.
-type ContextKey string
+// ClientInfoMiddleware attaches the client IP and user agent to the request context as domain.ClientInfo.
+func ClientInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := domain.WithClientInfo(c.Request.Context(), domain.ClientInfo{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// extractBearerToken reads the token from the Authorization header.
+// It writes a 401 response and returns false if the header is missing or malformed.
+func extractBearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		c.Abort()
+		return "", false
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization format must be Bearer <token>"})
+		c.Abort()
+		return "", false
+	}
 
-const AuthClaimsKey ContextKey = "firebaseAuthClaims"
+	return parts[1], true
+}
 
-func AuthMiddleware(fc *fbclient.Client) gin.HandlerFunc {
+// TokenAuthMiddleware tries each verifier in turn (e.g. local JWT, personal access token, Firebase)
+// and stores the Principal of the first one that accepts the token (see handler.GetPrincipal).
+// A verifier that recognizes the token but finds the account disabled, or not verified while the
+// verification policy requires it, ends the chain with a 403.
+// The verification errors are only echoed back in debug mode.
+func TokenAuthMiddleware(verifiers []ports.TokenVerifier, debugMode bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+		token, ok := extractBearerToken(c)
+		if !ok {
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization format must be Bearer <token>"})
+		var details []string
+		unavailable := false
+		for _, verifier := range verifiers {
+			principal, err := verifier.VerifyToken(c.Request.Context(), token)
+			if err == nil {
+				handler.SetPrincipal(c, principal)
+				c.Next()
+				return
+			}
+			if errors.Is(err, domain.ErrAccountDisabled) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "This account has been disabled"})
+				c.Abort()
+				return
+			}
+			if errors.Is(err, domain.ErrEmailNotVerified) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Email address not verified"})
+				c.Abort()
+				return
+			}
+			if !errors.Is(err, domain.ErrInvalidToken) {
+				// Keep trying: another verifier may still accept the token
+				log.Printf("Token verification error: %v", err)
+				unavailable = true
+			}
+			details = append(details, err.Error())
+		}
+
+		if unavailable {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify token"})
 			c.Abort()
 			return
 		}
 
-		idToken := parts[1]
+		response := gin.H{"error": "Invalid or expired token"}
+		if debugMode {
+			response["details"] = details
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		c.Abort()
+	}
+}
 
-		token, err := fc.AuthClient.VerifyIDToken(context.Background(), idToken)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token", "details": err.Error()})
+// RequirePermission allows the request only if one of the caller's roles grants the permission
+// (e.g. domain.PermissionProjectWrite). It must run after TokenAuthMiddleware, which stores
+// the caller's roles in the Principal. For Firebase ID tokens these are the roles custom
+// claim kept in sync by the FirebaseClaimsSync service. Requests made with a personal access token
+// also need the permission among the token's scopes.
+func RequirePermission(roles ports.RoleRepository, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := handler.GetPrincipal(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
 			c.Abort()
 			return
 		}
 
-		c.Set(string(AuthClaimsKey), token)
-		c.Set("userUID", token.UID)
+		allowed, err := roles.HasPermission(c.Request.Context(), principal.Roles, permission)
+		if err != nil {
+			log.Printf("Permission check error: %v", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to verify permissions"})
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions", "required": permission})
+			c.Abort()
+			return
+		}
+		if principal.Scopes != nil && !slices.Contains(principal.Scopes, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access token scope does not allow this action", "required": permission})
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
 }
-
-func GetAuthClaims(c *gin.Context) (*auth.Token, bool) {
-	value, exists := c.Get(string(AuthClaimsKey))
-	if !exists {
-		return nil, false
-	}
-	claims, ok := value.(*auth.Token)
-	return claims, ok
-}
@@ -0,0 +1,109 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+	"github.com/mitcheltastic/ManproBackend/internal/handler"
+	fbclient "github.com/mitcheltastic/ManproBackend/internal/infrastructure/firebase"
+)
+
+var (
+	testFakeSecret = []byte("fake-firebase-test-secret")
+	testProjectID  = "manpro-test"
+)
+
+// newFakeFirebaseRouter serves GET /whoami behind TokenAuthMiddleware with only the fake verifier,
+// echoing the Principal the middleware stored.
+func newFakeFirebaseRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	verifier, err := fbclient.NewFakeVerifier(testFakeSecret, testProjectID)
+	if err != nil {
+		t.Fatalf("NewFakeVerifier: %v", err)
+	}
+
+	r := gin.New()
+	r.GET("/whoami", TokenAuthMiddleware([]ports.TokenVerifier{verifier}, false), func(c *gin.Context) {
+		principal, ok := handler.GetPrincipal(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "no principal"})
+			return
+		}
+		c.JSON(http.StatusOK, principal)
+	})
+	return r
+}
+
+func serveWithToken(r *gin.Engine, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestTokenAuthMiddlewareAcceptsFakeFirebaseToken(t *testing.T) {
+	r := newFakeFirebaseRouter(t)
+	claims := fbclient.FakeTokenClaims{Email: "alice@example.com", EmailVerified: true, Roles: []string{"member"}}
+	token, err := fbclient.SignFakeToken(testFakeSecret, testProjectID, "fb-alice", claims, time.Hour)
+	if err != nil {
+		t.Fatalf("SignFakeToken: %v", err)
+	}
+
+	w := serveWithToken(r, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+	var principal domain.Principal
+	if err := json.Unmarshal(w.Body.Bytes(), &principal); err != nil {
+		t.Fatalf("decode principal: %v", err)
+	}
+	if principal.UserID != "fb-alice" || principal.Email != "alice@example.com" || !principal.EmailVerified {
+		t.Errorf("principal = %+v", principal)
+	}
+	if principal.Provider != domain.PrincipalProviderFirebase || !slices.Equal(principal.Roles, []string{"member"}) {
+		t.Errorf("principal = %+v, want a Firebase principal with the member role", principal)
+	}
+}
+
+func TestTokenAuthMiddlewareRejectsBadFakeFirebaseTokens(t *testing.T) {
+	claims := fbclient.FakeTokenClaims{Email: "mallory@example.com", EmailVerified: true}
+	tests := []struct {
+		name      string
+		secret    []byte
+		projectID string
+		ttl       time.Duration
+	}{
+		{"wrong secret", []byte("some-other-secret"), testProjectID, time.Hour},
+		{"wrong project", testFakeSecret, "another-project", time.Hour},
+		{"default project", testFakeSecret, "", time.Hour},
+		{"expired", testFakeSecret, testProjectID, -time.Minute},
+	}
+	r := newFakeFirebaseRouter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := fbclient.SignFakeToken(tt.secret, tt.projectID, "fb-mallory", claims, tt.ttl)
+			if err != nil {
+				t.Fatalf("SignFakeToken: %v", err)
+			}
+			if w := serveWithToken(r, token); w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, http.StatusUnauthorized, w.Body)
+			}
+		})
+	}
+
+	if w := serveWithToken(r, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("without a token: status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
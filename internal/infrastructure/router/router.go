@@ -1,10 +1,14 @@
 package router
 
 import (
+	"context"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mitcheltastic/ManproBackend/config" // Import for config
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
 	"github.com/mitcheltastic/ManproBackend/internal/handler" 
 	"github.com/mitcheltastic/ManproBackend/internal/pkg/security" 
 	"github.com/mitcheltastic/ManproBackend/internal/pkg/email"     // New Import for Email Sender
@@ -14,15 +18,37 @@ import (
 )
 
 // SetupRoutes registers all API routes and middleware.
-func SetupRoutes(r *gin.Engine, fbClient *fbclient.Client, dbClient *dbimpl.Client, cfg *config.Config) {
+func SetupRoutes(r *gin.Engine, fbClient fbclient.Auth, dbClient *dbimpl.Client, cfg *config.Config) {
 	
 	// --- Dependency Injection Setup (Wiring the Layers) ---
 	
 	// 1. Initialize Repository (Data Access)
 	authRepo := dbimpl.NewAuthRepository(dbClient.DB) 
+	tokenRepo := dbimpl.NewTokenRepository(dbClient.DB)
+	sessionRepo := dbimpl.NewSessionRepository(dbClient.DB)
+	roleRepo := dbimpl.NewRoleRepository(dbClient.DB)
+	auditRepo := dbimpl.NewAuditRepository(dbClient.DB)
+	revocationStore := dbimpl.NewRevocationStore(dbClient.DB)
+	attemptStore := dbimpl.NewAttemptStore(dbClient.DB)
+	mfaRepo := dbimpl.NewMFARepository(dbClient.DB)
+	accessTokenRepo := dbimpl.NewPersonalAccessTokenRepository(dbClient.DB)
+	orgRepo := dbimpl.NewOrganizationRepository(dbClient.DB)
+	projectRepo := dbimpl.NewProjectRepository(dbClient.DB)
+
+	// Expired revocation entries and failed-attempt counters are cleaned up in the background
+	go service.RunPruner(context.Background(), revocationStore, attemptStore, cfg.LoginAttemptWindow, cfg.RevocationPruneInterval)
 
 	// 2. Initialize JWT Service (The Token Generator)
-	jwtService := security.NewJWTService(cfg.JWTSecret, "manpro_backend")
+	jwtKeys, err := loadJWTKeys(cfg)
+	if err != nil {
+		log.Fatalf("Error loading JWT keys: %v", err)
+	}
+	jwtService := security.NewJWTService(jwtKeys, "manpro_backend", cfg.AccessTokenTTL)
+
+	passwordPolicy, err := newPasswordPolicy(cfg)
+	if err != nil {
+		log.Fatalf("Error loading password policy: %v", err)
+	}
 
 	// 3. Initialize Email Sender (The critical new piece)
 	emailSender := email.NewSMTPSender(
@@ -33,49 +59,278 @@ func SetupRoutes(r *gin.Engine, fbClient *fbclient.Client, dbClient *dbimpl.Clie
 		cfg.FromEmail,
 	)
 
-	// 4. Initialize Service (Business Logic)
-	// FIX: Pass the emailSender as the third argument
-	authService := service.NewAuthService(authRepo, jwtService, emailSender) 
+	// 4. Initialize Services (Business Logic)
+	mfaConfig := service.MFAConfig{
+		Issuer:        cfg.MFAIssuer,
+		EncryptionKey: []byte(cfg.MFAEncryptionSecret),
+		CodeHashKey:   []byte(cfg.CodeHashSecret),
+	}
+	lockoutConfig := service.LockoutConfig{
+		MaxAttemptsPerAccount: cfg.LoginMaxAttempts,
+		MaxAttemptsPerIP:      cfg.LoginIPMaxAttempts,
+		Window:                cfg.LoginAttemptWindow,
+		BaseDuration:          cfg.LockoutBaseDuration,
+		MaxDuration:           cfg.LockoutMaxDuration,
+		MaxResetCodeAttempts:  cfg.ResetCodeMaxAttempts,
+	}
+	firebaseClaimsSync := service.NewFirebaseClaimsService(authRepo, roleRepo, fbClient)
+	authService := service.NewAuthService(authRepo, tokenRepo, sessionRepo, roleRepo, revocationStore, attemptStore, mfaRepo, fbClient, firebaseClaimsSync, jwtService, emailSender, auditRepo, service.AuthServiceConfig{
+		AccessTokenTTL:          cfg.AccessTokenTTL,
+		RefreshTokenTTL:         cfg.RefreshTokenTTL,
+		EmailVerificationPolicy: cfg.EmailVerificationPolicy,
+		EmailVerificationTTL:    cfg.EmailVerificationTTL,
+		EmailVerificationURL:    cfg.EmailVerificationURL,
+		CodeHashKey:             []byte(cfg.CodeHashSecret),
+		PasswordHash:            cfg.PasswordHashParams(),
+		PasswordPolicy:          passwordPolicy,
+		Lockout:                 lockoutConfig,
+		MFA:                     mfaConfig,
+		MFAChallengeTTL:         cfg.MFAChallengeTTL,
+		MagicLinkEnabled:        cfg.MagicLinkEnabled,
+		MagicLinkTTL:            cfg.MagicLinkTTL,
+		MagicLinkURL:            cfg.MagicLinkURL,
+	})
+	mfaService := service.NewMFAService(mfaRepo, attemptStore, mfaConfig, lockoutConfig)
+	sessionService := service.NewSessionService(sessionRepo)
+	adminService := service.NewAdminService(authRepo, sessionRepo, roleRepo, auditRepo, authService, firebaseClaimsSync, projectRepo, orgRepo)
+	accessTokenService := service.NewPersonalAccessTokenService(accessTokenRepo, authRepo, roleRepo, auditRepo)
+	orgService := service.NewOrganizationService(orgRepo, authRepo, emailSender, auditRepo, service.OrganizationServiceConfig{
+		InvitationTTL: cfg.OrgInvitationTTL,
+		InvitationURL: cfg.OrgInvitationURL,
+	})
+	projectService := service.NewProjectService(projectRepo, authRepo, auditRepo)
 
 	// 5. Initialize Handler (HTTP Controller)
-	authHandler := handler.NewAuthHandler(authService)
+	authHandler := handler.NewAuthHandler(authService, cfg.DebugMode)
+	mfaHandler := handler.NewMFAHandler(mfaService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	adminHandler := handler.NewAdminHandler(adminService)
+	accessTokenHandler := handler.NewAccessTokenHandler(accessTokenService)
+	orgHandler := handler.NewOrganizationHandler(orgService, cfg.DebugMode)
+	projectHandler := handler.NewProjectHandler(projectService)
+
+	// --- Global Middleware ---
+
+	// Let services read request-scoped values (e.g. domain.ClientInfo) from the gin.Context they receive
+	r.ContextWithFallback = true
+	// Only the configured proxies may set the client IP (X-Forwarded-For); the lockout is keyed on it
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Error setting trusted proxies: %v", err)
+	}
+	r.Use(ClientInfoMiddleware())
 
 	// --- Public Routes ---
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "OK", "service": "Go Backend"})
 	})
 
+	// Public keys for verifying our JWTs (empty when signing with HS256)
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, jwtService.JWKS())
+	})
+
 	// --- V1 API Group ---
 	v1 := r.Group("/api/v1")
 	
 	// Public Auth Endpoints
 	v1.POST("/auth/register", authHandler.Register)
 	v1.POST("/auth/login", authHandler.Login)
+	v1.POST("/auth/login/mfa", authHandler.LoginMFA)
+	v1.POST("/auth/firebase", authHandler.FirebaseExchange)
 	v1.POST("/auth/forgot-password", authHandler.StartPasswordReset)
 	v1.POST("/auth/reset-password", authHandler.ResetPassword)
+	v1.POST("/auth/magic-link", authHandler.StartMagicLinkLogin)
+	v1.POST("/auth/magic-link/verify", authHandler.VerifyMagicLink)
+	v1.POST("/auth/refresh", authHandler.RefreshToken)
+	v1.POST("/auth/verify-email", authHandler.VerifyEmail)
+	v1.POST("/auth/resend-verification", authHandler.ResendVerification)
+
+	// Protected Routes. Every route authenticates with TokenAuthMiddleware; each group lists the
+	// verifiers of the token types it accepts. Logging out must keep working for unverified accounts,
+	// so only the logout routes ignore the verification policy. Personal access tokens and Firebase
+	// ID tokens can never manage credentials, sessions or other tokens.
+	requireVerified := cfg.EmailVerificationPolicy == domain.VerificationPolicyBlockProtected
+	localVerifier := service.NewLocalTokenVerifier(jwtService, revocationStore, sessionRepo, false)
+	verifiedLocalVerifier := service.NewLocalTokenVerifier(jwtService, revocationStore, sessionRepo, requireVerified)
+	accessTokenVerifier := service.NewAccessTokenVerifier(accessTokenService, requireVerified)
+	firebaseVerifier := service.NewFirebaseTokenVerifier(fbClient, authRepo, roleRepo)
+
+	sessionAuth := TokenAuthMiddleware([]ports.TokenVerifier{localVerifier}, cfg.DebugMode)
+	credentialAuth := TokenAuthMiddleware([]ports.TokenVerifier{verifiedLocalVerifier}, cfg.DebugMode)
+	tokenAuth := TokenAuthMiddleware([]ports.TokenVerifier{verifiedLocalVerifier, accessTokenVerifier}, cfg.DebugMode)
+	userAuth := TokenAuthMiddleware([]ports.TokenVerifier{verifiedLocalVerifier, firebaseVerifier}, cfg.DebugMode)
+	anyTokenAuth := TokenAuthMiddleware([]ports.TokenVerifier{verifiedLocalVerifier, accessTokenVerifier, firebaseVerifier}, cfg.DebugMode)
 
-	// Protected Routes (Require Firebase Authentication Middleware)
-	v1.Use(AuthMiddleware(fbClient))
+	v1.POST("/auth/logout", sessionAuth, authHandler.Logout)
+	v1.POST("/auth/logout-all", sessionAuth, authHandler.LogoutAll)
+
+	v1.GET("/me", tokenAuth, localProfileHandler)
+
+	me := v1.Group("/me")
+	me.Use(credentialAuth)
 	{
-		// Example protected endpoint: Get User Profile (using Firebase claims)
-		v1.GET("/profile", protectedProfileHandler)
+
+		// Credentials of the signed-in user
+		me.POST("/password", authHandler.ChangePassword)
+		me.POST("/email", authHandler.StartEmailChange)
+		me.POST("/email/confirm", authHandler.ConfirmEmailChange)
+
+		// Two-factor authentication management
+		me.POST("/mfa/totp/enroll", mfaHandler.EnrollTOTP)
+		me.POST("/mfa/totp/confirm", mfaHandler.ConfirmTOTP)
+		me.POST("/mfa/totp/disable", mfaHandler.DisableTOTP)
+
+		// Signed-in devices
+		me.GET("/sessions", sessionHandler.ListSessions)
+		me.DELETE("/sessions/:id", sessionHandler.RevokeSession)
+
+		// Personal access tokens for scripts and CI
+		me.GET("/tokens", accessTokenHandler.ListTokens)
+		me.POST("/tokens", accessTokenHandler.CreateToken)
+		me.DELETE("/tokens/:id", accessTokenHandler.RevokeToken)
 	}
+
+	// Admin Routes (Require a local JWT or personal access token whose roles, and scopes, grant the permission of each route)
+	canReadUsers := RequirePermission(roleRepo, domain.PermissionUserRead)
+	canWriteUsers := RequirePermission(roleRepo, domain.PermissionUserWrite)
+	canAssignRoles := RequirePermission(roleRepo, domain.PermissionRoleAssign)
+	canReadAudit := RequirePermission(roleRepo, domain.PermissionAuditRead)
+
+	admin := v1.Group("/admin")
+	admin.Use(tokenAuth)
+	{
+		admin.GET("/roles", canReadUsers, adminHandler.ListRoles)
+
+		admin.GET("/users", canReadUsers, adminHandler.ListUsers)
+		admin.GET("/users/:id", canReadUsers, adminHandler.GetUser) // :id is a user ID or an email address
+		admin.POST("/users/:id/disable", canWriteUsers, adminHandler.DisableUser)
+		admin.POST("/users/:id/enable", canWriteUsers, adminHandler.EnableUser)
+		admin.POST("/users/:id/password-reset", canWriteUsers, adminHandler.SendPasswordReset)
+		admin.POST("/users/:id/verify", canWriteUsers, adminHandler.MarkUserVerified)
+		admin.DELETE("/users/:id", canWriteUsers, adminHandler.DeleteUser)
+
+		admin.PUT("/users/:id/roles/:role", canAssignRoles, adminHandler.AssignRole)
+		admin.DELETE("/users/:id/roles/:role", canAssignRoles, adminHandler.RemoveRole)
+
+		admin.GET("/audit-events", canReadAudit, adminHandler.ListAuditEvents)
+	}
+
+	// Organization Routes (Require a local JWT or the Firebase ID token of a linked account; the caller's role
+	// in each organization is checked by the service)
+	// Declining only needs the emailed token, so invitees without an account can decline too.
+	v1.POST("/orgs/invitations/decline", orgHandler.DeclineInvitation)
+
+	orgs := v1.Group("/orgs")
+	orgs.Use(userAuth)
+	{
+		orgs.GET("", orgHandler.ListOrganizations)
+		orgs.POST("", orgHandler.CreateOrganization)
+		orgs.POST("/invitations/accept", orgHandler.AcceptInvitation)
+
+		orgs.GET("/:id", orgHandler.GetOrganization)
+		orgs.PATCH("/:id", orgHandler.RenameOrganization)
+		orgs.DELETE("/:id", orgHandler.DeleteOrganization)
+
+		orgs.GET("/:id/members", orgHandler.ListMembers)
+		orgs.PUT("/:id/members/:userId", orgHandler.UpdateMemberRole)
+		orgs.DELETE("/:id/members/:userId", orgHandler.RemoveMember)
+
+		orgs.GET("/:id/invitations", orgHandler.ListInvitations)
+		orgs.POST("/:id/invitations", orgHandler.InviteMember)
+		orgs.DELETE("/:id/invitations/:invitationId", orgHandler.RevokeInvitation)
+	}
+
+	// Project Routes (Require the project permission, with any token of a local or linked account;
+	// the caller's role in each project is checked by the service)
+	// :id is a project ID or key.
+	canReadProjects := RequirePermission(roleRepo, domain.PermissionProjectRead)
+	canWriteProjects := RequirePermission(roleRepo, domain.PermissionProjectWrite)
+	canDeleteProjects := RequirePermission(roleRepo, domain.PermissionProjectDelete)
+
+	projects := v1.Group("/projects")
+	projects.Use(anyTokenAuth)
+	{
+		projects.GET("", canReadProjects, projectHandler.ListProjects)
+		projects.POST("", canWriteProjects, projectHandler.CreateProject)
+
+		projects.GET("/:id", canReadProjects, projectHandler.GetProject)
+		projects.PATCH("/:id", canWriteProjects, projectHandler.UpdateProject)
+		projects.POST("/:id/archive", canWriteProjects, projectHandler.ArchiveProject)
+		projects.POST("/:id/unarchive", canWriteProjects, projectHandler.UnarchiveProject)
+		projects.DELETE("/:id", canDeleteProjects, projectHandler.DeleteProject)
+
+		projects.GET("/:id/members", canReadProjects, projectHandler.ListMembers)
+		projects.POST("/:id/members", canWriteProjects, projectHandler.AddMember)
+		projects.PUT("/:id/members/:userId", canWriteProjects, projectHandler.UpdateMemberRole)
+		projects.DELETE("/:id/members/:userId", canWriteProjects, projectHandler.RemoveMember)
+	}
+
+	// Protected Routes (Accept a local JWT, a personal access token or a Firebase ID token)
+	// Every route here needs a permission: linked Firebase users are authorized by their local roles,
+	// others by the roles custom claim or the default role.
+	canReadProfile := RequirePermission(roleRepo, domain.PermissionProfileRead)
+
+	// Example protected endpoint: Get User Profile (from the provider-neutral Principal)
+	v1.GET("/profile", anyTokenAuth, canReadProfile, protectedProfileHandler)
+}
+
+// newPasswordPolicy builds the password policy (validated by config.Validate) and loads the
+// breached-password list, if configured.
+func newPasswordPolicy(cfg *config.Config) (*security.PasswordPolicy, error) {
+	policy := cfg.PasswordPolicy()
+	if cfg.BreachedPasswordsPath != "" {
+		breached, err := security.OpenBreachedPasswords(cfg.BreachedPasswordsPath)
+		if err != nil {
+			return nil, err
+		}
+		policy.Breached = breached
+	}
+	return policy, nil
+}
+
+// loadJWTKeys builds the signing/verification key set for the configured JWT algorithm.
+func loadJWTKeys(cfg *config.Config) (*security.KeySet, error) {
+	if cfg.JWTAlgorithm == security.AlgorithmHS256 {
+		return security.NewHMACKeySet(cfg.JWTSecret), nil
+	}
+	return security.LoadKeySet(cfg.JWTAlgorithm, cfg.JWTSigningKeyID, cfg.JWTSigningKeyPath, cfg.JWTVerificationKeys)
 }
 
 // protectedProfileHandler is a sample handler that retrieves the user's information
-// from the request context after the token has been verified.
+// from the request context after the token has been verified, whichever provider issued it.
 func protectedProfileHandler(c *gin.Context) {
-	claims, ok := GetAuthClaims(c)
+	principal, ok := handler.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Authentication context missing"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Welcome! You are authenticated.",
+		"user_id":        principal.UserID,
+		"email":          principal.Email,
+		"email_verified": principal.EmailVerified,
+		"name":           principal.Name,
+		"roles":          principal.Roles,
+		"provider":       principal.Provider,
+	})
+}
+
+// localProfileHandler returns the identity of a caller with a local account.
+func localProfileHandler(c *gin.Context) {
+	principal, ok := handler.GetPrincipal(c)
 	if !ok {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Authentication context missing"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Welcome! You are authenticated.",
-		"user_id": claims.UID,
-		"email":   claims.Claims["email"], 
-		"name":    claims.Claims["name"], 
-		"claims_raw": claims.Claims,
+		"message":        "Welcome! You are authenticated.",
+		"user_id":        principal.UserID,
+		"email":          principal.Email,
+		"email_verified": principal.EmailVerified,
+		"roles":          principal.Roles,
+		"expires_at":     principal.ExpiresAt,
 	})
-}
\ No newline at end of file
+}
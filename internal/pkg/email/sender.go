@@ -10,6 +10,16 @@ import (
 // Sender defines the interface for sending email notifications.
 type Sender interface {
 	SendPasswordResetCode(toEmail string, code string) error
+	// SendVerificationEmail sends the email verification token. link may be empty if no frontend URL is configured.
+	SendVerificationEmail(toEmail string, token string, link string) error
+	// SendEmailChangeCode sends the code confirming a change to this (new) address.
+	SendEmailChangeCode(toEmail string, code string) error
+	// SendMagicLink sends a single-use login token; link may be empty if no frontend URL is configured.
+	SendMagicLink(toEmail string, token string, link string) error
+	// SendEmailChangeNotice tells the current address that a change to newEmail was requested.
+	SendEmailChangeNotice(toEmail string, newEmail string) error
+	// SendOrganizationInvitation sends the token to accept or decline joining orgName; link may be empty if no frontend URL is configured.
+	SendOrganizationInvitation(toEmail string, orgName string, inviterName string, token string, link string) error
 }
 
 // SMTPSender is the concrete implementation of the Sender interface using SMTP.
@@ -34,6 +44,93 @@ func NewSMTPSender(host, port, user, pass, from string) Sender {
 
 // SendPasswordResetCode formats and sends the 6-digit code via email.
 func (s *SMTPSender) SendPasswordResetCode(toEmail string, code string) error {
+	subject := "Your Password Reset Code"
+	body := fmt.Sprintf("Your 6-digit password reset code is: %s. This code will expire in 15 minutes. Please use it immediately to reset your password.", code)
+
+	if err := s.send(toEmail, subject, body); err != nil {
+		return err
+	}
+
+	log.Printf("SUCCESS: Password reset code sent to %s.", toEmail)
+	return nil
+}
+
+// SendVerificationEmail sends the token used to confirm ownership of the email address.
+func (s *SMTPSender) SendVerificationEmail(toEmail string, token string, link string) error {
+	subject := "Verify Your Email Address"
+	body := fmt.Sprintf("Welcome! Please verify your email address using this token: %s", token)
+	if link != "" {
+		body = fmt.Sprintf("Welcome! Please verify your email address by opening this link: %s", link)
+	}
+
+	if err := s.send(toEmail, subject, body); err != nil {
+		return err
+	}
+
+	log.Printf("SUCCESS: Verification email sent to %s.", toEmail)
+	return nil
+}
+
+// SendEmailChangeCode sends the code that confirms the user owns the new email address.
+func (s *SMTPSender) SendEmailChangeCode(toEmail string, code string) error {
+	subject := "Confirm Your New Email Address"
+	body := fmt.Sprintf("Your 6-digit code to confirm this email address is: %s. This code will expire in 15 minutes.", code)
+
+	if err := s.send(toEmail, subject, body); err != nil {
+		return err
+	}
+
+	log.Printf("SUCCESS: Email change code sent to %s.", toEmail)
+	return nil
+}
+
+// SendMagicLink sends the single-use token (or link) that signs the user in without a password.
+func (s *SMTPSender) SendMagicLink(toEmail string, token string, link string) error {
+	subject := "Your Login Link"
+	body := fmt.Sprintf("Use this single-use token to log in: %s. If you did not request it, you can ignore this email.", token)
+	if link != "" {
+		body = fmt.Sprintf("Open this single-use link to log in: %s. If you did not request it, you can ignore this email.", link)
+	}
+
+	if err := s.send(toEmail, subject, body); err != nil {
+		return err
+	}
+
+	log.Printf("SUCCESS: Magic link sent to %s.", toEmail)
+	return nil
+}
+
+// SendEmailChangeNotice warns the current address that the account email is about to change.
+func (s *SMTPSender) SendEmailChangeNotice(toEmail string, newEmail string) error {
+	subject := "Your Email Address Is Being Changed"
+	body := fmt.Sprintf("A request was made to change the email address of your account to %s. If this was not you, change your password immediately.", newEmail)
+
+	if err := s.send(toEmail, subject, body); err != nil {
+		return err
+	}
+
+	log.Printf("SUCCESS: Email change notice sent to %s.", toEmail)
+	return nil
+}
+
+// SendOrganizationInvitation invites the recipient to an organization. The same token accepts or declines.
+func (s *SMTPSender) SendOrganizationInvitation(toEmail string, orgName string, inviterName string, token string, link string) error {
+	subject := fmt.Sprintf("You Have Been Invited to %s", orgName)
+	body := fmt.Sprintf("%s invited you to join %s. Use this token to accept or decline the invitation: %s", inviterName, orgName, token)
+	if link != "" {
+		body = fmt.Sprintf("%s invited you to join %s. Open this link to accept or decline the invitation: %s", inviterName, orgName, link)
+	}
+
+	if err := s.send(toEmail, subject, body); err != nil {
+		return err
+	}
+
+	log.Printf("SUCCESS: Organization invitation sent to %s.", toEmail)
+	return nil
+}
+
+// send delivers a plain-text email over SMTP with STARTTLS.
+func (s *SMTPSender) send(toEmail string, subject string, body string) error {
 	addr := fmt.Sprintf("%s:%s", s.host, s.port) 
 	
 	// Authentication setup
@@ -42,11 +139,10 @@ func (s *SMTPSender) SendPasswordResetCode(toEmail string, code string) error {
 	auth := smtp.PlainAuth("", s.username, s.password, s.host) 
 
 	// Email content (MIME headers + body)
-	subject := "Subject: Your Password Reset Code\r\n"
+	subjectHeader := fmt.Sprintf("Subject: %s\r\n", subject)
 	mime := "MIME-version: 1.0;\r\nContent-Type: text/plain; charset=\"UTF-8\";\r\n\r\n"
-	body := fmt.Sprintf("Your 6-digit password reset code is: %s. This code will expire in 15 minutes. Please use it immediately to reset your password.", code)
 	
-	msg := []byte(subject + mime + body)
+	msg := []byte(subjectHeader + mime + body)
 	
 	// 1. Establish the UNENCRYPTED connection
 	client, err := smtp.Dial(addr)
@@ -97,6 +193,5 @@ func (s *SMTPSender) SendPasswordResetCode(toEmail string, code string) error {
 		log.Printf("Warning: Failed to quit SMTP session: %v", err)
 	}
 
-	log.Printf("SUCCESS: Password reset code sent to %s.", toEmail)
 	return nil
 }
\ No newline at end of file
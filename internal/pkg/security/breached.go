@@ -0,0 +1,122 @@
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// BreachedPasswordChecker reports whether a password appears in a list of breached passwords.
+type BreachedPasswordChecker interface {
+	IsBreached(password string) (bool, error)
+}
+
+// OpenBreachedPasswords opens a local breached-password list in the format of Have I Been Pwned.
+// No network access is needed:
+//   - a file holds one upper-case SHA-1 hash per line (optionally followed by ":count") and is loaded
+//     into memory, so use a subset such as the most common passwords;
+//   - a directory holds one file per 5-character hash prefix (e.g. "5BAA6" or "5BAA6.txt") listing the
+//     remaining 35 characters per line, as returned by the range API; only the matching file is read.
+func OpenBreachedPasswords(path string) (BreachedPasswordChecker, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	if info.IsDir() {
+		return &breachedPrefixDir{dir: path}, nil
+	}
+	return loadBreachedHashFile(path)
+}
+
+// sha1Hex returns the upper-case hex SHA-1 of the password, as used by the lists.
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// breachedHashList is a breached-password list held in memory as sorted SHA-1 digests.
+type breachedHashList struct {
+	hashes [][sha1.Size]byte
+}
+
+// loadBreachedHashFile reads a file of full SHA-1 hashes.
+func loadBreachedHashFile(path string) (*breachedHashList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	list := &breachedHashList{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(text, ":")
+
+		var digest [sha1.Size]byte
+		if n, err := hex.Decode(digest[:], []byte(hash)); err != nil || n != sha1.Size || len(hash) != 2*sha1.Size {
+			return nil, fmt.Errorf("breached password list %s, line %d: not a SHA-1 hash", path, line)
+		}
+		list.hashes = append(list.hashes, digest)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	slices.SortFunc(list.hashes, func(a, b [sha1.Size]byte) int { return bytes.Compare(a[:], b[:]) })
+	list.hashes = slices.Compact(list.hashes)
+	return list, nil
+}
+
+// IsBreached looks the password's SHA-1 digest up with a binary search.
+func (l *breachedHashList) IsBreached(password string) (bool, error) {
+	digest := sha1.Sum([]byte(password))
+	_, found := slices.BinarySearchFunc(l.hashes, digest, func(a, b [sha1.Size]byte) int { return bytes.Compare(a[:], b[:]) })
+	return found, nil
+}
+
+// breachedPrefixDir is a breached-password list split into one file per 5-character hash prefix.
+type breachedPrefixDir struct {
+	dir string
+}
+
+// IsBreached scans the file of the password's hash prefix for the remaining characters.
+// A missing prefix file means no breached password has that prefix.
+func (d *breachedPrefixDir) IsBreached(password string) (bool, error) {
+	hash := sha1Hex(password)
+	prefix, suffix := hash[:5], hash[5:]
+
+	file, err := os.Open(filepath.Join(d.dir, prefix))
+	if errors.Is(err, os.ErrNotExist) {
+		file, err = os.Open(filepath.Join(d.dir, prefix+".txt"))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to open breached password range %s: %w", prefix, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		candidate, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(candidate, suffix) {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read breached password range %s: %w", prefix, err)
+	}
+	return false, nil
+}
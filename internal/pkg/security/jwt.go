@@ -1,6 +1,8 @@
 package security
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -8,55 +10,193 @@ import (
 )
 
 // Define custom claims struct that embeds the standard JWT claims
+// The unique token ID is carried in the standard 'jti' claim (RegisteredClaims.ID).
 type UserClaims struct {
 	UserID uuid.UUID `json:"user_id"`
 	Email  string    `json:"email"`
+	// SessionID links the access token to the refresh token family it was issued with.
+	SessionID uuid.UUID `json:"sid"`
+	// EmailVerified mirrors users.is_verified at the time the token was issued.
+	EmailVerified bool `json:"email_verified"`
+	// Roles lists the user's roles at the time the token was issued (see domain.Role*).
+	Roles []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ChallengeClaims are carried by short-lived tokens that prove one step of a multi-step flow
+// (e.g. the password step of an MFA login). The audience names the flow, so a challenge token
+// can never be used as an access token or for a different flow.
+type ChallengeClaims struct {
+	UserID uuid.UUID `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// TokenSubject holds the user data that is embedded into a newly issued access token.
+type TokenSubject struct {
+	UserID        uuid.UUID
+	Email         string
+	SessionID     uuid.UUID
+	EmailVerified bool
+	Roles         []string
+}
+
 // JWTService defines the interface for token operations.
 type JWTService interface {
-	GenerateToken(userID uuid.UUID, email string) (string, error)
+	// GenerateToken returns the signed access token and its expiry time.
+	GenerateToken(subject TokenSubject) (string, time.Time, error)
+	ValidateToken(tokenString string) (*UserClaims, error)
+
+	// GenerateChallengeToken issues a short-lived token bound to a purpose (e.g. "mfa_login").
+	GenerateChallengeToken(userID uuid.UUID, purpose string, ttl time.Duration) (string, time.Time, error)
+	// ValidateChallengeToken checks a challenge token for the given purpose and returns its claims.
+	// The token ID (jti) lets callers make the challenge single-use.
+	ValidateChallengeToken(tokenString string, purpose string) (*ChallengeClaims, error)
+
+	// JWKS returns the public keys other services can use to verify our tokens.
+	JWKS() JWKSet
 }
 
 // jwtServiceImpl is the concrete implementation of the JWTService.
 type jwtServiceImpl struct {
-	// IMPORTANT: In a real application, this should be loaded from a secret environment variable (e.g., config.JWTSecret).
-	secretKey []byte 
-	issuer    string
+	// keys signs new tokens and holds every key accepted for verification (see NewHMACKeySet, LoadKeySet).
+	keys   *KeySet
+	issuer string
+	ttl    time.Duration
 }
 
 // NewJWTService creates a new JWT service instance.
-func NewJWTService(secret string, issuer string) JWTService {
+// ttl controls how long an access token stays valid; refresh tokens are used to extend the session.
+func NewJWTService(keys *KeySet, issuer string, ttl time.Duration) JWTService {
 	return &jwtServiceImpl{
-		secretKey: []byte(secret),
-		issuer:    issuer,
+		keys:   keys,
+		issuer: issuer,
+		ttl:    ttl,
 	}
 }
 
 // GenerateToken creates a signed JWT string containing user information.
-func (s *jwtServiceImpl) GenerateToken(userID uuid.UUID, email string) (string, error) {
+func (s *jwtServiceImpl) GenerateToken(subject TokenSubject) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.ttl)
+
 	// Define the token claims
 	claims := UserClaims{
+		UserID:        subject.UserID,
+		Email:         subject.Email,
+		SessionID:     subject.SessionID,
+		EmailVerified: subject.EmailVerified,
+		Roles:         subject.Roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),              // jti: lets a single token be revoked on logout
+			ExpiresAt: jwt.NewNumericDate(expiresAt), // Short-lived; clients renew it via /auth/refresh
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    s.issuer,
+			Subject:   subject.UserID.String(),
+		},
+	}
+
+	// Sign the token with the current signing key (its ID goes into the 'kid' header)
+	tokenString, err := s.keys.sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// ValidateToken parses a signed JWT string and returns its claims.
+// The signature, issuer, expiry and not-before times are all checked.
+func (s *jwtServiceImpl) ValidateToken(tokenString string) (*UserClaims, error) {
+	claims := &UserClaims{}
+
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		claims,
+		s.keys.keyFunc,
+		// Only accept the algorithms of our own keys, to prevent algorithm confusion attacks
+		jwt.WithValidMethods(s.keys.validMethods()),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	// The subject must match the user_id claim, otherwise the token was not issued by us
+	if claims.UserID == uuid.Nil || claims.Subject != claims.UserID.String() {
+		return nil, errors.New("invalid token subject")
+	}
+	if claims.ID == "" || claims.IssuedAt == nil {
+		return nil, errors.New("token is missing its ID or issue time")
+	}
+	// Challenge tokens always carry an audience; access tokens never do
+	if len(claims.Audience) > 0 {
+		return nil, errors.New("token is not an access token")
+	}
+
+	return claims, nil
+}
+
+// GenerateChallengeToken creates a signed token that is only accepted by ValidateChallengeToken for the same purpose.
+func (s *jwtServiceImpl) GenerateChallengeToken(userID uuid.UUID, purpose string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims := ChallengeClaims{
 		UserID: userID,
-		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)), // Token expires in 24 hours
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{s.challengeAudience(purpose)},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    s.issuer,
 			Subject:   userID.String(),
 		},
 	}
 
-	// Create the token using the claims and the HS256 signing method
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := s.keys.sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return tokenString, expiresAt, nil
+}
 
-	// Sign the token with the secret key
-	tokenString, err := token.SignedString(s.secretKey)
+// ValidateChallengeToken parses a challenge token and checks that it was issued for the given purpose.
+func (s *jwtServiceImpl) ValidateChallengeToken(tokenString string, purpose string) (*ChallengeClaims, error) {
+	claims := &ChallengeClaims{}
+
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		claims,
+		s.keys.keyFunc,
+		jwt.WithValidMethods(s.keys.validMethods()),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.challengeAudience(purpose)),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("invalid challenge token: %w", err)
+	}
+	if !token.Valid || claims.UserID == uuid.Nil || claims.Subject != claims.UserID.String() || claims.ID == "" {
+		return nil, errors.New("invalid challenge token")
 	}
 
-	return tokenString, nil
-}
\ No newline at end of file
+	return claims, nil
+}
+
+// challengeAudience namespaces the purpose with the issuer, e.g. "manpro_backend:mfa_login".
+func (s *jwtServiceImpl) challengeAudience(purpose string) string {
+	return s.issuer + ":" + purpose
+}
+
+// JWKS returns the public verification keys (empty when signing with an HMAC secret).
+func (s *jwtServiceImpl) JWKS() JWKSet {
+	return s.keys.JWKS()
+}
@@ -0,0 +1,263 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported JWT signing algorithms (config.JWTAlgorithm).
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// verificationKey is a public key (or the HMAC secret) that tokens can be verified with.
+type verificationKey struct {
+	id     string
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+// KeySet holds the key used to sign new tokens and every key accepted for verification.
+// During a rotation the previous public keys stay in the set until their tokens have expired.
+type KeySet struct {
+	signingID     string
+	signingMethod jwt.SigningMethod
+	signingKey    interface{}
+	verification  map[string]verificationKey
+}
+
+// NewHMACKeySet returns a key set that signs and verifies with a shared HS256 secret.
+// HMAC keys are never published in the JWKS.
+func NewHMACKeySet(secret string) *KeySet {
+	key := []byte(secret)
+	return &KeySet{
+		signingMethod: jwt.SigningMethodHS256,
+		signingKey:    key,
+		verification: map[string]verificationKey{
+			"": {method: jwt.SigningMethodHS256, key: key},
+		},
+	}
+}
+
+// LoadKeySet loads an asymmetric key set from PEM files.
+// privateKeyPath is the current signing key (PKCS#8, or PKCS#1 for RSA) published under keyID.
+// verificationKeys maps the IDs of previous keys to their public key files (PKIX).
+func LoadKeySet(algorithm string, keyID string, privateKeyPath string, verificationKeys map[string]string) (*KeySet, error) {
+	if keyID == "" {
+		return nil, errors.New("a key ID is required for asymmetric signing keys")
+	}
+
+	method, err := signingMethodFor(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := loadPrivateKey(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing key: %w", err)
+	}
+	if err := checkKeyMatchesMethod(signer.Public(), method); err != nil {
+		return nil, fmt.Errorf("signing key %q: %w", keyID, err)
+	}
+
+	set := &KeySet{
+		signingID:     keyID,
+		signingMethod: method,
+		signingKey:    signer,
+		verification: map[string]verificationKey{
+			keyID: {id: keyID, method: method, key: signer.Public()},
+		},
+	}
+
+	for id, path := range verificationKeys {
+		if _, exists := set.verification[id]; exists {
+			return nil, fmt.Errorf("duplicate key ID %q", id)
+		}
+		public, err := loadPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load verification key %q: %w", id, err)
+		}
+		publicMethod, err := methodForPublicKey(public)
+		if err != nil {
+			return nil, fmt.Errorf("verification key %q: %w", id, err)
+		}
+		set.verification[id] = verificationKey{id: id, method: publicMethod, key: public}
+	}
+
+	return set, nil
+}
+
+// sign signs the token with the current key, setting the 'kid' header for asymmetric keys.
+func (k *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signingMethod, claims)
+	if k.signingID != "" {
+		token.Header["kid"] = k.signingID
+	}
+	return token.SignedString(k.signingKey)
+}
+
+// keyFunc selects the verification key by the token's 'kid' header and checks that
+// the token uses the algorithm of that key (prevents algorithm confusion attacks).
+func (k *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := k.verification[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+	return key.key, nil
+}
+
+// validMethods lists the algorithms of all verification keys.
+func (k *KeySet) validMethods() []string {
+	seen := map[string]bool{}
+	var methods []string
+	for _, key := range k.verification {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWK is a single public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	// RSA public key
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519 public key
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public verification keys. It is empty for HMAC key sets.
+func (k *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+
+	for _, key := range k.verification {
+		jwk := JWK{Use: "sig", Algorithm: key.method.Alg(), KeyID: key.id}
+
+		switch public := key.key.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue // HMAC secrets must never be published
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	// Stable order, so the document (and HTTP caches) do not change between requests
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+// signingMethodFor maps a configured algorithm name to its JWT signing method.
+func signingMethodFor(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported asymmetric JWT algorithm %q", algorithm)
+	}
+}
+
+// methodForPublicKey picks the signing method matching the key type.
+func methodForPublicKey(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", public)
+	}
+}
+
+// checkKeyMatchesMethod ensures the configured algorithm fits the loaded key.
+func checkKeyMatchesMethod(public crypto.PublicKey, method jwt.SigningMethod) error {
+	expected, err := methodForPublicKey(public)
+	if err != nil {
+		return err
+	}
+	if expected.Alg() != method.Alg() {
+		return fmt.Errorf("key type does not match algorithm %s", method.Alg())
+	}
+	return nil
+}
+
+// loadPrivateKey reads an RSA or Ed25519 private key from a PEM file.
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("private key must be PKCS#8 or PKCS#1 PEM")
+}
+
+// loadPublicKey reads an RSA or Ed25519 public key from a PEM file.
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// readPEM reads the first PEM block of a file.
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return block, nil
+}
@@ -2,25 +2,120 @@ package security
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"log"
 	"math/big"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword takes a plaintext password and returns its bcrypt hash.
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
+// Argon2idParams are the cost parameters of new password hashes. They are stored in every hash,
+// so raising them only affects new hashes (and old ones are upgraded on the next login, see NeedsRehash).
+type Argon2idParams struct {
+	// Memory is the memory cost in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// DefaultArgon2idParams follows the OWASP recommendation for Argon2id (19 MiB, 2 iterations, 1 lane).
+var DefaultArgon2idParams = Argon2idParams{Memory: 19 * 1024, Iterations: 2, Parallelism: 1}
+
+// Validate reports whether the parameters can be used to hash passwords.
+func (p Argon2idParams) Validate() error {
+	if p.Iterations < 1 || p.Parallelism < 1 {
+		return errors.New("argon2id iterations and parallelism must be at least 1")
+	}
+	if p.Memory < 8*uint32(p.Parallelism) {
+		return errors.New("argon2id memory must be at least 8 KiB per lane")
+	}
+	return nil
+}
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+)
+
+// HashPassword takes a plaintext password and returns its Argon2id hash in the PHC string format:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash> (unpadded base64).
+// Unlike bcrypt, the whole password is used, however long it is.
+func HashPassword(password string, params Argon2idParams) (string, error) {
+	if err := params.Validate(); err != nil {
+		return "", err
+	}
+
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
 		return "", err
 	}
-	return string(bytes), nil
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, argon2idKeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
 // CheckPasswordHash compares a plaintext password with a hashed password.
+// Both Argon2id hashes and legacy bcrypt hashes ($2a$, $2b$, $2y$) are accepted.
 // Returns nil on success, or an error if they do not match.
 func CheckPasswordHash(password, hash string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	}
+
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return err
+	}
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return errors.New("password does not match")
+	}
+	return nil
+}
+
+// NeedsRehash reports whether the hash was made with another algorithm (bcrypt) or with other
+// parameters than the current ones, and should be replaced after the next successful login.
+func NeedsRehash(hash string, params Argon2idParams) bool {
+	current, _, _, err := decodeArgon2idHash(hash)
+	return err != nil || current != params
+}
+
+// decodeArgon2idHash parses a hash created by HashPassword.
+func decodeArgon2idHash(hash string) (params Argon2idParams, salt []byte, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return params, nil, nil, errors.New("not an argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.New("unsupported argon2id version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, errors.New("invalid argon2id parameters")
+	}
+	if err := params.Validate(); err != nil {
+		return params, nil, nil, err
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, errors.New("invalid argon2id salt")
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return params, nil, nil, errors.New("invalid argon2id hash")
+	}
+	return params, salt, key, nil
 }
 
 // GenerateNumericCode generates a cryptographically secure, random numeric string of the given length.
@@ -0,0 +1,175 @@
+package security
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Codes of the rules a password can violate (see PolicyViolation).
+const (
+	PasswordTooShort     = "too_short"
+	PasswordTooLong      = "too_long"
+	PasswordPersonalInfo = "contains_personal_info"
+	PasswordTooWeak      = "too_weak"
+	PasswordBreached     = "breached"
+)
+
+// MinPasswordMaxLength is the lowest allowed maximum length: long passphrases must always be accepted.
+const MinPasswordMaxLength = 64
+
+// PolicyViolation is one rule a password does not meet.
+type PolicyViolation struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicy checks new passwords (register, reset and change-password).
+// Lengths are counted in characters, not bytes.
+type PasswordPolicy struct {
+	MinLength int
+	MaxLength int
+	// MinStrength is the lowest accepted EstimateStrength score (0-4).
+	MinStrength int
+	// Breached is optional; without it the breached-password check is skipped.
+	Breached BreachedPasswordChecker
+}
+
+// Validate reports whether the policy settings are usable.
+func (p *PasswordPolicy) Validate() error {
+	if p.MinLength < 1 {
+		return fmt.Errorf("password minimum length must be at least 1, got %d", p.MinLength)
+	}
+	if p.MaxLength < MinPasswordMaxLength || p.MaxLength < p.MinLength {
+		return fmt.Errorf("password maximum length must be at least %d and the minimum length, got %d", MinPasswordMaxLength, p.MaxLength)
+	}
+	if p.MinStrength < 0 || p.MinStrength > 4 {
+		return fmt.Errorf("password minimum strength must be between 0 and 4, got %d", p.MinStrength)
+	}
+	return nil
+}
+
+// Check returns every rule the password violates (empty if it is accepted).
+// personalInfo holds the user's name and email address, which must not appear in the password.
+func (p *PasswordPolicy) Check(password string, personalInfo ...string) []PolicyViolation {
+	var violations []PolicyViolation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violations = append(violations, PolicyViolation{PasswordTooShort, fmt.Sprintf("must be at least %d characters long", p.MinLength)})
+	}
+	if length > p.MaxLength {
+		// Nothing else is checked: hashing very long inputs is wasted work
+		return append(violations, PolicyViolation{PasswordTooLong, fmt.Sprintf("must be at most %d characters long", p.MaxLength)})
+	}
+
+	if containsPersonalInfo(password, personalInfo) {
+		violations = append(violations, PolicyViolation{PasswordPersonalInfo, "must not contain your name or email address"})
+	}
+
+	if EstimateStrength(password) < p.MinStrength {
+		violations = append(violations, PolicyViolation{PasswordTooWeak, "is too easy to guess; use a longer password or a passphrase"})
+	}
+
+	if p.Breached != nil {
+		breached, err := p.Breached.IsBreached(password)
+		if err != nil {
+			// Fail open: an unreadable list must not block every sign-up
+			log.Printf("ERROR: Breached password check failed: %v", err)
+		} else if breached {
+			violations = append(violations, PolicyViolation{PasswordBreached, "has appeared in a data breach; choose a different password"})
+		}
+	}
+
+	return violations
+}
+
+// containsPersonalInfo reports whether the password contains the full name or email address,
+// any word of the name, or the local part of the email address (ignoring case).
+func containsPersonalInfo(password string, personalInfo []string) bool {
+	lowered := strings.ToLower(password)
+
+	for _, info := range personalInfo {
+		info = strings.ToLower(strings.TrimSpace(info))
+		candidates := []string{info}
+		if local, _, found := strings.Cut(info, "@"); found {
+			candidates = append(candidates, local)
+		}
+		candidates = append(candidates, strings.FieldsFunc(info, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+
+		for _, candidate := range candidates {
+			// Very short fragments (initials, "jo") would reject too many good passwords
+			if utf8.RuneCountInString(candidate) >= 3 && strings.Contains(lowered, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EstimateStrength scores how hard the password is to guess, from 0 (trivial) to 4 (very strong).
+// The estimate is the entropy of the character classes used, discounted for repeated characters
+// and for runs such as "abcd" or "4321", which guessing tools try first.
+func EstimateStrength(password string) int {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 0
+	}
+
+	var lower, upper, digit, other bool
+	for _, r := range runes {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	pool := 0
+	if lower {
+		pool += 26
+	}
+	if upper {
+		pool += 26
+	}
+	if digit {
+		pool += 10
+	}
+	if other {
+		pool += 33
+	}
+
+	// Characters that repeat or continue a run from the previous one add almost nothing
+	effective := 1.0
+	for i := 1; i < len(runes); i++ {
+		delta := runes[i] - runes[i-1]
+		if delta >= -1 && delta <= 1 {
+			effective += 0.25
+		} else {
+			effective++
+		}
+	}
+
+	bits := effective * math.Log2(float64(pool))
+	switch {
+	case bits < 28:
+		return 0
+	case bits < 36:
+		return 1
+	case bits < 60:
+		return 2
+	case bits < 80:
+		return 3
+	default:
+		return 4
+	}
+}
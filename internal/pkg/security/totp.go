@@ -0,0 +1,137 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP parameters used for every enrollment (RFC 6238 defaults, supported by all authenticator apps).
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30 * time.Second
+	// TOTPSkew is the number of time steps accepted before and after the current one (clock drift).
+	TOTPSkew = 1
+)
+
+// base32NoPadding is the encoding authenticator apps expect for the shared secret.
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a new random 160-bit shared secret, base32 encoded.
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base32NoPadding.EncodeToString(secret), nil
+}
+
+// TOTPURI builds the otpauth:// URI that authenticator apps read from a QR code.
+func TOTPURI(issuer string, accountName string, secret string) string {
+	label := url.PathEscape(issuer + ":" + accountName)
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", TOTPDigits))
+	params.Set("period", fmt.Sprintf("%d", int(TOTPPeriod.Seconds())))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, params.Encode())
+}
+
+// TOTPTimeStep returns the RFC 6238 time step (counter) for the given time.
+func TOTPTimeStep(t time.Time) int64 {
+	return t.Unix() / int64(TOTPPeriod.Seconds())
+}
+
+// GenerateHOTP computes an RFC 4226 one-time password for a counter value.
+// newHash selects the HMAC hash (sha1.New for standard TOTP; RFC 6238 also allows SHA-256/512).
+func GenerateHOTP(key []byte, counter int64, digits int, newHash func() hash.Hash) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(newHash, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	binCode := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	otp := binCode % uint32(math.Pow10(digits))
+	return fmt.Sprintf("%0*d", digits, otp)
+}
+
+// GenerateTOTP computes the standard (SHA-1, 6 digits, 30 seconds) TOTP code for a base32 secret.
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return GenerateHOTP(key, TOTPTimeStep(t), TOTPDigits, sha1.New), nil
+}
+
+// ValidateTOTP checks a code against the time steps around t (see TOTPSkew).
+// It returns the matching time step so callers can reject replays of the same code.
+func ValidateTOTP(secret string, code string, t time.Time) (int64, bool) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil || len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	current := TOTPTimeStep(t)
+	for step := current - TOTPSkew; step <= current+TOTPSkew; step++ {
+		expected := GenerateHOTP(key, step, TOTPDigits, sha1.New)
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCode returns a random single-use recovery code formatted as xxxxx-xxxxx.
+func GenerateRecoveryCode() (string, error) {
+	// Unambiguous lowercase alphabet (no 0/o, 1/l/i)
+	const charset = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	// rand.Int picks each character uniformly; a random byte modulo 31 would favour the first characters
+	b := make([]byte, 10)
+	for i := range b {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = charset[index.Int64()]
+	}
+	return string(b[:5]) + "-" + string(b[5:]), nil
+}
+
+// NormalizeRecoveryCode lowercases a user-entered recovery code and restores the dash,
+// so "ABCDE FGHJK" and "abcde-fghjk" hash to the same value.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != 10 {
+		return code
+	}
+	return code[:5] + "-" + code[5:]
+}
+
+// decodeTOTPSecret decodes a base32 secret, tolerating lowercase input and padding.
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.TrimRight(strings.ToUpper(strings.TrimSpace(secret)), "=")
+	key, err := base32NoPadding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,123 @@
+package security
+
+import (
+	"crypto/sha1"
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Key is the SHA-1 seed of RFC 6238 Appendix B.
+const rfc6238Key = "12345678901234567890"
+
+// rfc6238Vectors are the SHA-1 rows of the RFC 6238 Appendix B table (8-digit codes).
+var rfc6238Vectors = []struct {
+	unix int64
+	otp  string
+}{
+	{59, "94287082"},
+	{1111111109, "07081804"},
+	{1111111111, "14050471"},
+	{1234567890, "89005924"},
+	{2000000000, "69279037"},
+	{20000000000, "65353130"},
+}
+
+func rfc6238Secret() string {
+	return base32NoPadding.EncodeToString([]byte(rfc6238Key))
+}
+
+func TestGenerateHOTPMatchesRFC6238Vectors(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		step := TOTPTimeStep(time.Unix(v.unix, 0))
+		if got := GenerateHOTP([]byte(rfc6238Key), step, 8, sha1.New); got != v.otp {
+			t.Errorf("T=%d: GenerateHOTP = %s, want %s", v.unix, got, v.otp)
+		}
+	}
+}
+
+func TestGenerateTOTPMatchesRFC6238Vectors(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		// A 6-digit code is the last 6 digits of the 8-digit one (same truncated value, smaller modulus).
+		want := v.otp[len(v.otp)-TOTPDigits:]
+		got, err := GenerateTOTP(rfc6238Secret(), time.Unix(v.unix, 0))
+		if err != nil {
+			t.Fatalf("T=%d: GenerateTOTP: %v", v.unix, err)
+		}
+		if got != want {
+			t.Errorf("T=%d: GenerateTOTP = %s, want %s", v.unix, got, want)
+		}
+	}
+}
+
+func TestValidateTOTPAcceptsRFC6238Vectors(t *testing.T) {
+	for _, v := range rfc6238Vectors {
+		at := time.Unix(v.unix, 0)
+		step, ok := ValidateTOTP(rfc6238Secret(), v.otp[len(v.otp)-TOTPDigits:], at)
+		if !ok {
+			t.Errorf("T=%d: ValidateTOTP rejected the RFC code", v.unix)
+			continue
+		}
+		if want := TOTPTimeStep(at); step != want {
+			t.Errorf("T=%d: ValidateTOTP step = %d, want %d", v.unix, step, want)
+		}
+	}
+}
+
+func TestValidateTOTPSkewWindow(t *testing.T) {
+	secret := rfc6238Secret()
+	now := time.Unix(1234567890, 0)
+	current := TOTPTimeStep(now)
+
+	tests := []struct {
+		name   string
+		offset int64 // time steps relative to now
+		valid  bool
+	}{
+		{"two steps behind", -2, false},
+		{"one step behind", -1, true},
+		{"current step", 0, true},
+		{"one step ahead", 1, true},
+		{"two steps ahead", 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := GenerateTOTP(secret, now.Add(time.Duration(tt.offset)*TOTPPeriod))
+			if err != nil {
+				t.Fatalf("GenerateTOTP: %v", err)
+			}
+			step, ok := ValidateTOTP(secret, code, now)
+			if ok != tt.valid {
+				t.Fatalf("ValidateTOTP ok = %v, want %v", ok, tt.valid)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("ValidateTOTP step = %d, want %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	if _, ok := ValidateTOTP(rfc6238Secret(), "94287082", now); ok {
+		t.Error("accepted an 8-digit code")
+	}
+	if _, ok := ValidateTOTP("not base32!", "287082", now); ok {
+		t.Error("accepted an invalid secret")
+	}
+}
+
+func TestDecodeTOTPSecretToleratesLowercaseAndPadding(t *testing.T) {
+	padded := base32.StdEncoding.EncodeToString([]byte(rfc6238Key))
+	for _, secret := range []string{padded, " " + padded + " ", rfc6238Secret()} {
+		lower := strings.ToLower(secret)
+		key, err := decodeTOTPSecret(lower)
+		if err != nil {
+			t.Fatalf("decodeTOTPSecret(%q): %v", lower, err)
+		}
+		if string(key) != rfc6238Key {
+			t.Errorf("decodeTOTPSecret(%q) = %q", lower, key)
+		}
+	}
+}
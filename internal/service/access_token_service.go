@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+	"github.com/mitcheltastic/ManproBackend/internal/pkg/security"
+)
+
+// accessTokenTouchInterval limits how often a request updates the last_used_at of its token.
+const accessTokenTouchInterval = time.Minute
+
+// accessTokenPrefixLength is how many random characters of a token are kept for display.
+const accessTokenPrefixLength = 6
+
+// errInvalidAccessToken is returned for unknown, revoked and expired tokens alike.
+var errInvalidAccessToken = errors.New("invalid personal access token")
+
+// PersonalAccessTokenService is the concrete implementation of the ports.PersonalAccessTokenService interface.
+type PersonalAccessTokenService struct {
+	TokenRepo ports.PersonalAccessTokenRepository
+	AuthRepo  ports.AuthRepository
+	RoleRepo  ports.RoleRepository
+	Audit     ports.AuditLogger
+}
+
+// NewPersonalAccessTokenService creates a new instance of the PersonalAccessTokenService.
+func NewPersonalAccessTokenService(tokenRepo ports.PersonalAccessTokenRepository, authRepo ports.AuthRepository, roleRepo ports.RoleRepository, audit ports.AuditLogger) ports.PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		TokenRepo: tokenRepo,
+		AuthRepo:  authRepo,
+		RoleRepo:  roleRepo,
+		Audit:     audit,
+	}
+}
+
+// CreateToken issues a token limited to the requested scopes. Users can only grant scopes
+// their own roles hold, so a token never has more access than its owner.
+func (s *PersonalAccessTokenService) CreateToken(ctx context.Context, userID uuid.UUID, req domain.CreatePersonalAccessTokenRequest) (resp *domain.CreatedPersonalAccessTokenResponse, err error) {
+	defer func() {
+		details := map[string]string{"name": req.Name, "scopes": strings.Join(req.Scopes, ",")}
+		if resp != nil {
+			details["token_id"] = resp.ID.String()
+		}
+		recordAudit(ctx, s.Audit, auditResult(domain.AuditEventAccessTokenCreated, userID, userID, err, details))
+	}()
+
+	// 1. Validate the expiry and the scopes against the owner's current roles
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	roles, err := s.RoleRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+	scopes := slices.Compact(slices.Sorted(slices.Values(req.Scopes)))
+	for _, scope := range scopes {
+		allowed, err := s.RoleRepo.HasPermission(ctx, roles, scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check scope: %w", err)
+		}
+		if !allowed {
+			return nil, fmt.Errorf("scope not permitted: %s", scope)
+		}
+	}
+
+	// 2. Generate the token; only its hash is stored
+	secret, err := security.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	raw := domain.PersonalAccessTokenPrefix + secret
+
+	token := domain.PersonalAccessToken{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        req.Name,
+		TokenPrefix: raw[:len(domain.PersonalAccessTokenPrefix)+accessTokenPrefixLength],
+		TokenHash:   security.HashToken(raw),
+		Scopes:      scopes,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedAt:   now,
+	}
+	if err := s.TokenRepo.CreateToken(ctx, token); err != nil {
+		return nil, fmt.Errorf("failed to save access token: %w", err)
+	}
+
+	return &domain.CreatedPersonalAccessTokenResponse{
+		PersonalAccessToken: token,
+		Token:               raw,
+	}, nil
+}
+
+// ListTokens returns the user's tokens that have not been revoked.
+func (s *PersonalAccessTokenService) ListTokens(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	tokens, err := s.TokenRepo.ListTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during token lookup: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeToken revokes one of the user's tokens; requests using it are rejected from then on.
+func (s *PersonalAccessTokenService) RevokeToken(ctx context.Context, userID uuid.UUID, tokenID uuid.UUID) (err error) {
+	defer func() {
+		details := map[string]string{"token_id": tokenID.String()}
+		recordAudit(ctx, s.Audit, auditResult(domain.AuditEventAccessTokenRevoked, userID, userID, err, details))
+	}()
+
+	revoked, err := s.TokenRepo.RevokeToken(ctx, userID, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+	if !revoked {
+		return errors.New("access token not found")
+	}
+	return nil
+}
+
+// Authenticate looks the token up by its hash, checks that it and its owner are still active,
+// and records the use. The owner's roles are loaded fresh, so role changes apply immediately.
+func (s *PersonalAccessTokenService) Authenticate(ctx context.Context, rawToken string) (*domain.PersonalAccessTokenAuth, error) {
+	// 1. Look up the token
+	if !strings.HasPrefix(rawToken, domain.PersonalAccessTokenPrefix) {
+		return nil, errInvalidAccessToken
+	}
+	token, err := s.TokenRepo.GetTokenByHash(ctx, security.HashToken(rawToken))
+	if err != nil {
+		return nil, fmt.Errorf("repository error during token lookup: %w", err)
+	}
+	if token == nil || !token.IsActive(time.Now()) {
+		return nil, errInvalidAccessToken
+	}
+
+	// 2. Load the owner and their current roles
+	user, err := s.AuthRepo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during user lookup: %w", err)
+	}
+	if user == nil {
+		return nil, errInvalidAccessToken
+	}
+	if user.IsDisabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+	roles, err := s.RoleRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+
+	// 3. Record the use (informational only, so errors do not fail the request)
+	if err := s.TokenRepo.TouchToken(ctx, token.ID, accessTokenTouchInterval); err != nil {
+		log.Printf("Access token touch error: %v", err)
+	}
+
+	return &domain.PersonalAccessTokenAuth{
+		Token: *token,
+		User:  *user,
+		Roles: roles,
+	}, nil
+}
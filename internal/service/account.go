@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/pkg/security"
+)
+
+// emailChangeCodeTTL is how long the code sent to a new email address stays valid.
+const emailChangeCodeTTL = 15 * time.Minute
+
+// ChangePassword replaces the password of a signed-in user. The current password is required,
+// so a stolen access token alone cannot be used to take over the account.
+func (s *AuthService) ChangePassword(ctx context.Context, identity domain.TokenIdentity, req domain.ChangePasswordRequest) (err error) {
+	defer func() {
+		details := map[string]string{"revoke_other_sessions": strconv.FormatBool(req.RevokeOtherSessions)}
+		recordAudit(ctx, s.Audit, auditResult(domain.AuditEventPasswordChanged, identity.UserID, identity.UserID, err, details))
+	}()
+
+	// 1. Load the user and check the current password (with the login's brute-force protection)
+	user, err := s.AuthRepo.GetUserByID(ctx, identity.UserID)
+	if err != nil {
+		return errors.New("internal error retrieving user")
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if err := s.checkCurrentPassword(ctx, "password", user, req.CurrentPassword); err != nil {
+		return err
+	}
+	if err := s.checkPasswordPolicy("new_password", req.NewPassword, user.Name, user.Email); err != nil {
+		return err
+	}
+
+	// 2. Hash and store the new password
+	newHashedPassword, err := security.HashPassword(req.NewPassword, s.Config.PasswordHash)
+	if err != nil {
+		return errors.New("failed to hash new password")
+	}
+	if err := s.AuthRepo.UpdateUserPassword(ctx, user.ID.String(), newHashedPassword); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	// 3. Optionally sign out every other device; the current session stays valid
+	if req.RevokeOtherSessions {
+		if err := s.SessionRepo.RevokeAllSessions(ctx, user.ID, identity.SessionID); err != nil {
+			return fmt.Errorf("failed to revoke other sessions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// StartEmailChange stores a pending change and emails a confirmation code to the new address.
+// The current address only receives a notice; users.email is not touched until ConfirmEmailChange.
+func (s *AuthService) StartEmailChange(ctx context.Context, userID uuid.UUID, req domain.ChangeEmailRequest) error {
+	// 1. Load the user and check the current password
+	user, err := s.AuthRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return errors.New("internal error retrieving user")
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if err := s.checkCurrentPassword(ctx, "email_change", user, req.CurrentPassword); err != nil {
+		return err
+	}
+
+	// 2. The new address must differ and must not belong to another account
+	if strings.EqualFold(req.NewEmail, user.Email) {
+		return errors.New("new email is the same as the current email")
+	}
+	existingUser, err := s.AuthRepo.GetUserByEmail(ctx, req.NewEmail)
+	if err != nil {
+		return fmt.Errorf("repository error during lookup: %w", err)
+	}
+	if existingUser != nil {
+		return errors.New("a user with this email already exists")
+	}
+
+	// 3. Save only the keyed hash of the code, like password reset codes
+	code := security.GenerateNumericCode(6)
+	expiresAt := time.Now().Add(emailChangeCodeTTL)
+	if err := s.AuthRepo.CreateEmailChangeCode(ctx, user.ID, req.NewEmail, security.HashCode(s.Config.CodeHashKey, code), expiresAt); err != nil {
+		return fmt.Errorf("failed to save email change code: %w", err)
+	}
+
+	// 4. Send the code to the new address; without it the change cannot be completed
+	if err := s.EmailSender.SendEmailChangeCode(req.NewEmail, code); err != nil {
+		return fmt.Errorf("failed to send email change code: %w", err)
+	}
+
+	// 5. Warn the current address. A delivery failure is only logged.
+	if err := s.EmailSender.SendEmailChangeNotice(user.Email, req.NewEmail); err != nil {
+		log.Printf("ERROR: Failed to send email change notice to %s: %v", user.Email, err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange checks the code sent to the new address and updates users.email.
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, userID uuid.UUID, req domain.ConfirmEmailChangeRequest) (err error) {
+	var newEmail string
+	defer func() {
+		event := auditResult(domain.AuditEventEmailChanged, userID, userID, err, nil)
+		event.Email = newEmail
+		recordAudit(ctx, s.Audit, event)
+	}()
+
+	// 1. Check and consume the code (invalidated after too many wrong guesses)
+	codeHash := security.HashCode(s.Config.CodeHashKey, req.Code)
+	newEmail, err = s.AuthRepo.ConsumeEmailChangeCode(ctx, userID, codeHash, s.Config.Lockout.MaxResetCodeAttempts)
+	if err != nil {
+		return err
+	}
+
+	// 2. Apply the change. The email claim of existing access tokens is updated on the next refresh.
+	if err := s.AuthRepo.UpdateUserEmail(ctx, userID, newEmail); err != nil {
+		if err.Error() == "a user with this email already exists" {
+			return err
+		}
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+
+	log.Printf("User %s changed their email address", userID)
+	return nil
+}
+
+// checkCurrentPassword re-authenticates a signed-in user before a sensitive change.
+// Wrong passwords count towards the lockout of the given action.
+func (s *AuthService) checkCurrentPassword(ctx context.Context, action string, user *domain.User, password string) error {
+	attemptKeys := s.limiter().attemptKeys(ctx, action, user.Email)
+	if err := s.limiter().checkLockout(ctx, attemptKeys); err != nil {
+		return err
+	}
+
+	// Accounts created through Firebase have no password yet and must set one via forgot-password
+	if user.HashedPassword == "" || security.CheckPasswordHash(password, user.HashedPassword) != nil {
+		s.limiter().recordFailure(ctx, attemptKeys)
+		return errors.New("current password is incorrect")
+	}
+
+	s.limiter().clearFailures(ctx, attemptKeys)
+	return nil
+}
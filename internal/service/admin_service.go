@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// AdminService is the concrete implementation of the ports.AdminService interface.
+type AdminService struct {
+	AuthRepo    ports.AuthRepository
+	SessionRepo ports.SessionRepository
+	RoleRepo    ports.RoleRepository
+	AuditRepo   ports.AuditRepository
+	// AuthService sends forced password resets through the regular forgot-password flow.
+	AuthService ports.AuthService
+	// FirebaseClaims is optional; without it role changes are not pushed to Firebase custom claims.
+	FirebaseClaims ports.FirebaseClaimsSync
+	// ProjectRepo hands the projects of a deleted user to another project admin.
+	ProjectRepo ports.ProjectRepository
+	// OrgRepo is checked before a deletion, so no organization loses its last owner.
+	OrgRepo ports.OrganizationRepository
+}
+
+// NewAdminService creates a new instance of the AdminService.
+func NewAdminService(authRepo ports.AuthRepository, sessionRepo ports.SessionRepository, roleRepo ports.RoleRepository, auditRepo ports.AuditRepository, authService ports.AuthService, firebaseClaims ports.FirebaseClaimsSync, projectRepo ports.ProjectRepository, orgRepo ports.OrganizationRepository) ports.AdminService {
+	return &AdminService{
+		AuthRepo:       authRepo,
+		SessionRepo:    sessionRepo,
+		RoleRepo:       roleRepo,
+		AuditRepo:      auditRepo,
+		AuthService:    authService,
+		FirebaseClaims: firebaseClaims,
+		ProjectRepo:    projectRepo,
+		OrgRepo:        orgRepo,
+	}
+}
+
+// ListUsers applies the paging defaults and returns one page of matching users.
+func (s *AdminService) ListUsers(ctx context.Context, req domain.ListUsersRequest) (*domain.UserListResponse, error) {
+	page, pageSize := pagination(req.Page, req.PageSize)
+
+	users, total, err := s.AuthRepo.ListUsers(ctx, domain.UserFilter{
+		Query:    req.Query,
+		Role:     req.Role,
+		Verified: req.Verified,
+		Disabled: req.Disabled,
+		Limit:    pageSize,
+		Offset:   (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository error during user listing: %w", err)
+	}
+
+	return &domain.UserListResponse{
+		Users:    users,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetUser looks the user up by ID or email and adds their roles.
+func (s *AdminService) GetUser(ctx context.Context, idOrEmail string) (*domain.AdminUserResponse, error) {
+	var user *domain.User
+	var err error
+	if userID, parseErr := uuid.Parse(idOrEmail); parseErr == nil {
+		user, err = s.AuthRepo.GetUserByID(ctx, userID)
+	} else {
+		user, err = s.AuthRepo.GetUserByEmail(ctx, idOrEmail)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository error during lookup: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	roles, err := s.RoleRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user roles: %w", err)
+	}
+
+	return &domain.AdminUserResponse{User: *user, Roles: roles}, nil
+}
+
+// SetUserDisabled disables or re-enables the account. Disabling also revokes every session, so
+// re-enabling later does not bring old sessions back; access tokens stop working immediately
+// because the revocation check rejects tokens of disabled accounts.
+func (s *AdminService) SetUserDisabled(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, disabled bool) (err error) {
+	defer func() {
+		eventType := domain.AuditEventUserEnabled
+		if disabled {
+			eventType = domain.AuditEventUserDisabled
+		}
+		recordAudit(ctx, s.AuditRepo, auditResult(eventType, actorID, userID, err, nil))
+	}()
+
+	if disabled && actorID == userID {
+		return errors.New("cannot disable your own account")
+	}
+
+	found, err := s.AuthRepo.SetUserDisabled(ctx, userID, disabled)
+	if err != nil {
+		return fmt.Errorf("failed to update account status: %w", err)
+	}
+	if !found {
+		return errors.New("user not found")
+	}
+
+	if disabled {
+		if err := s.SessionRepo.RevokeAllSessions(ctx, userID, uuid.Nil); err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+
+	log.Printf("ADMIN: User %s set disabled=%t for user %s", actorID, disabled, userID)
+	s.syncFirebaseClaims(ctx, userID)
+	return nil
+}
+
+// SendPasswordReset starts the forgot-password flow on behalf of the user.
+func (s *AdminService) SendPasswordReset(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.AuthRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("repository error during lookup: %w", err)
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	if _, err := s.AuthService.StartPasswordReset(ctx, domain.ForgotPasswordRequest{Email: user.Email}); err != nil {
+		return fmt.Errorf("failed to start password reset: %w", err)
+	}
+	return nil
+}
+
+// MarkUserVerified marks the user's email address as verified without the emailed token.
+func (s *AdminService) MarkUserVerified(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.AuthRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("repository error during lookup: %w", err)
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.AuthRepo.MarkUserVerified(ctx, userID); err != nil {
+		return fmt.Errorf("failed to mark user as verified: %w", err)
+	}
+	return nil
+}
+
+// DeleteUser permanently deletes the account. A linked Firebase account is not deleted.
+// The user's projects are first handed to another project admin. The account is kept if the user
+// is the last owner of an organization or a project has no other admin, so neither is left without
+// someone who can manage it.
+func (s *AdminService) DeleteUser(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) (err error) {
+	var details map[string]string
+	defer func() {
+		recordAudit(ctx, s.AuditRepo, auditResult(domain.AuditEventUserDeleted, actorID, userID, err, details))
+	}()
+
+	if actorID == userID {
+		return errors.New("cannot delete your own account")
+	}
+
+	soleOwned, err := s.soleOwnedOrganizations(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(soleOwned) > 0 {
+		details = map[string]string{"organizations": strings.Join(soleOwned, ",")}
+		return errors.New("user is the only owner of an organization")
+	}
+
+	blocked, err := s.ProjectRepo.TransferOwnedProjects(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to transfer projects: %w", err)
+	}
+	if len(blocked) > 0 {
+		details = map[string]string{"projects": strings.Join(blocked, ",")}
+		return errors.New("user is the only admin of a project")
+	}
+
+	found, err := s.AuthRepo.DeleteUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if !found {
+		return errors.New("user not found")
+	}
+
+	log.Printf("ADMIN: User %s deleted user %s", actorID, userID)
+	return nil
+}
+
+// soleOwnedOrganizations returns the IDs of the organizations in which the user is the only owner.
+func (s *AdminService) soleOwnedOrganizations(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	memberships, err := s.OrgRepo.ListUserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during organization lookup: %w", err)
+	}
+
+	var soleOwned []string
+	for _, membership := range memberships {
+		if membership.Role != domain.OrgRoleOwner {
+			continue
+		}
+		owners, err := s.OrgRepo.CountOwners(ctx, membership.ID)
+		if err != nil {
+			return nil, fmt.Errorf("repository error during owner lookup: %w", err)
+		}
+		if owners <= 1 {
+			soleOwned = append(soleOwned, membership.ID.String())
+		}
+	}
+	return soleOwned, nil
+}
+
+// ListRoles returns every role with its permissions.
+func (s *AdminService) ListRoles(ctx context.Context) ([]domain.Role, error) {
+	roles, err := s.RoleRepo.ListRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during role listing: %w", err)
+	}
+	return roles, nil
+}
+
+// AssignRole grants a role. The new role is included in the user's tokens from their next refresh.
+func (s *AdminService) AssignRole(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, role string) (err error) {
+	defer func() {
+		details := map[string]string{"role": role}
+		recordAudit(ctx, s.AuditRepo, auditResult(domain.AuditEventRoleAssigned, actorID, userID, err, details))
+	}()
+
+	if err := s.RoleRepo.AssignRole(ctx, userID, role); err != nil {
+		return err
+	}
+
+	log.Printf("ADMIN: User %s granted role %q to user %s", actorID, role, userID)
+	s.syncFirebaseClaims(ctx, userID)
+	return nil
+}
+
+// RemoveRole revokes a role. Administrators cannot remove their own admin role.
+func (s *AdminService) RemoveRole(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, role string) (err error) {
+	defer func() {
+		details := map[string]string{"role": role}
+		recordAudit(ctx, s.AuditRepo, auditResult(domain.AuditEventRoleRemoved, actorID, userID, err, details))
+	}()
+
+	if actorID == userID && role == domain.RoleAdmin {
+		return errors.New("cannot remove your own admin role")
+	}
+
+	found, err := s.RoleRepo.RemoveRole(ctx, userID, role)
+	if err != nil {
+		return fmt.Errorf("failed to remove role: %w", err)
+	}
+	if !found {
+		// Nothing changed, so there is nothing to log or sync; tell a missing user from a missing role
+		user, err := s.AuthRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("repository error during user lookup: %w", err)
+		}
+		if user == nil {
+			return errors.New("user not found")
+		}
+		return errors.New("role not assigned")
+	}
+
+	log.Printf("ADMIN: User %s removed role %q from user %s", actorID, role, userID)
+	s.syncFirebaseClaims(ctx, userID)
+	return nil
+}
+
+// syncFirebaseClaims pushes the user's changed roles (none while disabled) to Firebase. Failures are only logged: the
+// change is already saved, and the reconciliation command (cmd/firebaseclaims) repairs the claims.
+func (s *AdminService) syncFirebaseClaims(ctx context.Context, userID uuid.UUID) {
+	if s.FirebaseClaims == nil {
+		return
+	}
+	if err := s.FirebaseClaims.SyncUser(ctx, userID); err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+}
+
+// ListAuditEvents applies the paging defaults and returns one page of audit events, newest first.
+func (s *AdminService) ListAuditEvents(ctx context.Context, req domain.ListAuditEventsRequest) (*domain.AuditEventListResponse, error) {
+	page, pageSize := pagination(req.Page, req.PageSize)
+
+	filter := domain.AuditFilter{
+		Email:  req.Email,
+		Type:   req.Type,
+		From:   req.From,
+		To:     req.To,
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	}
+	if req.UserID != "" {
+		userID, err := uuid.Parse(req.UserID)
+		if err != nil {
+			return nil, errors.New("invalid user ID")
+		}
+		filter.UserID = userID
+	}
+
+	events, total, err := s.AuditRepo.ListEvents(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during audit listing: %w", err)
+	}
+
+	return &domain.AuditEventListResponse{
+		Events:   events,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// pagination applies the defaults and limits of the admin list endpoints to a 1-based page.
+func pagination(page int, pageSize int) (int, int) {
+	page = max(page, 1)
+	if pageSize <= 0 {
+		pageSize = domain.DefaultPageSize
+	}
+	return page, min(pageSize, domain.MaxPageSize)
+}
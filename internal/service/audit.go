@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// recordAudit fills in the client IP and user agent from the request context and appends the event.
+// A failure to write the audit log is logged but never fails the request itself.
+func recordAudit(ctx context.Context, logger ports.AuditLogger, event domain.AuditEvent) {
+	client := domain.ClientInfoFromContext(ctx)
+	event.IP = client.IP
+	event.UserAgent = client.UserAgent
+
+	if err := logger.Record(ctx, event); err != nil {
+		log.Printf("ERROR: Failed to record audit event %s: %v", event.Type, err)
+	}
+}
+
+// auditResult builds an event performed by actorID on subjectID, with the outcome taken from err.
+// On failure the error message is kept in the details.
+func auditResult(eventType string, actorID uuid.UUID, subjectID uuid.UUID, err error, details map[string]string) domain.AuditEvent {
+	event := domain.AuditEvent{
+		Type:      eventType,
+		Outcome:   domain.AuditOutcomeSuccess,
+		ActorID:   actorID,
+		SubjectID: subjectID,
+		Details:   details,
+	}
+	if err != nil {
+		event.Outcome = domain.AuditOutcomeFailure
+		if event.Details == nil {
+			event.Details = map[string]string{}
+		}
+		event.Details["error"] = err.Error()
+	}
+	return event
+}
+
+// auditSignIn records the result of a flow that signs the user in (register, login, password reset).
+// On success the signed-in user is both actor and subject; on failure only the email is known.
+func (s *AuthService) auditSignIn(ctx context.Context, eventType string, email string, resp *domain.AuthResponse, err error, details map[string]string) {
+	var userID uuid.UUID
+	if err == nil && resp != nil {
+		userID = resp.UserID
+		email = resp.Email
+		if resp.MFARequired {
+			if details == nil {
+				details = map[string]string{}
+			}
+			details["mfa_required"] = "true"
+		}
+	}
+
+	event := auditResult(eventType, userID, userID, err, details)
+	event.Email = email
+	recordAudit(ctx, s.Audit, event)
+}
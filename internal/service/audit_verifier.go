@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// auditVerifyBatchSize is the number of audit events loaded per query while walking the chain.
+const auditVerifyBatchSize = 1000
+
+// VerifyAuditChain walks the audit log in ID order and recomputes every hash. It stops at the first
+// event whose prev_hash does not match the hash before it, or whose hash does not match its contents.
+// Events written before hash chaining are skipped, but only while no chained event has been seen.
+// If checkpoint is not nil, the event it names must still exist with the same hash.
+func VerifyAuditChain(ctx context.Context, repo ports.AuditRepository, checkpoint *domain.AuditChainCheckpoint) (*domain.AuditChainReport, error) {
+	report := &domain.AuditChainReport{}
+	var afterID int64
+	chainStarted := false
+
+	for {
+		events, err := repo.ListEventsAfter(ctx, afterID, auditVerifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load audit events after %d: %w", afterID, err)
+		}
+
+		for _, event := range events {
+			afterID = event.ID
+
+			if event.Hash == "" {
+				if chainStarted {
+					report.BrokenAtID = event.ID
+					report.Problem = "event has no hash but follows chained events"
+					return report, nil
+				}
+				report.LegacyEvents++
+				continue
+			}
+			chainStarted = true
+
+			if event.PrevHash != report.LastHash {
+				report.BrokenAtID = event.ID
+				report.Problem = "prev_hash does not match the hash of the previous event (an event was removed or reordered)"
+				return report, nil
+			}
+			if event.ComputeHash() != event.Hash {
+				report.BrokenAtID = event.ID
+				report.Problem = "hash does not match the event contents (the event was modified)"
+				return report, nil
+			}
+
+			if checkpoint != nil && event.ID == checkpoint.ID && event.Hash != checkpoint.Hash {
+				report.BrokenAtID = event.ID
+				report.Problem = "hash does not match the checkpoint (the chain was rebuilt)"
+				return report, nil
+			}
+
+			report.CheckedEvents++
+			report.LastID = event.ID
+			report.LastHash = event.Hash
+		}
+
+		if len(events) < auditVerifyBatchSize {
+			break
+		}
+	}
+
+	if checkpoint != nil && checkpoint.ID > report.LastID {
+		report.BrokenAtID = checkpoint.ID
+		report.Problem = "checkpoint event is missing (events were removed from the end of the log)"
+	}
+	return report, nil
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,25 +16,82 @@ import (
 	"github.com/mitcheltastic/ManproBackend/internal/pkg/security"
 )
 
+// mfaLoginPurpose binds challenge tokens to the second step of the login.
+const mfaLoginPurpose = "mfa_login"
+
 // AuthService is the concrete implementation of the ports.AuthService interface.
 type AuthService struct {
 	AuthRepo ports.AuthRepository
+	TokenRepo ports.TokenRepository
+	SessionRepo ports.SessionRepository
+	RoleRepo ports.RoleRepository
+	Revocations ports.RevocationStore
+	Attempts ports.AttemptStore
+	MFARepo ports.MFARepository
+	// FirebaseVerifier is optional; without it /auth/firebase is disabled.
+	FirebaseVerifier ports.FirebaseTokenVerifier
+	// FirebaseClaims is optional; without it roles are not mirrored into Firebase custom claims.
+	FirebaseClaims ports.FirebaseClaimsSync
 	JWTService security.JWTService 
 	EmailSender email.Sender // CRITICAL: Email Sender dependency must be here
+	Audit ports.AuditLogger
+	Config AuthServiceConfig
+}
+
+// AuthServiceConfig holds the tunable settings of the AuthService (loaded from config.Config).
+type AuthServiceConfig struct {
+	// AccessTokenTTL must match the JWTService lifetime; it bounds how long revocation entries are kept.
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// EmailVerificationPolicy is one of the domain.VerificationPolicy* values.
+	EmailVerificationPolicy string
+	EmailVerificationTTL    time.Duration
+	EmailVerificationURL    string
+
+	// CodeHashKey is the server-side key used to hash short codes (password reset codes) before storage.
+	CodeHashKey []byte
+
+	// PasswordHash is the Argon2id cost of new password hashes.
+	PasswordHash security.Argon2idParams
+	// PasswordPolicy checks every new password (register, reset and change-password).
+	PasswordPolicy *security.PasswordPolicy
+
+	Lockout LockoutConfig
+
+	MFA MFAConfig
+	// MFAChallengeTTL is how long the user has to enter the second factor after the password step.
+	MFAChallengeTTL time.Duration
+
+	// MagicLinkEnabled turns on passwordless login; MagicLinkURL is the optional frontend page for the link.
+	MagicLinkEnabled bool
+	MagicLinkTTL     time.Duration
+	MagicLinkURL     string
 }
 
 // NewAuthService creates a new instance of the AuthService.
-// CRITICAL: The third argument (emailSender) must be accepted here.
-func NewAuthService(authRepo ports.AuthRepository, jwtService security.JWTService, emailSender email.Sender) ports.AuthService {
+func NewAuthService(authRepo ports.AuthRepository, tokenRepo ports.TokenRepository, sessionRepo ports.SessionRepository, roleRepo ports.RoleRepository, revocations ports.RevocationStore, attempts ports.AttemptStore, mfaRepo ports.MFARepository, firebaseVerifier ports.FirebaseTokenVerifier, firebaseClaims ports.FirebaseClaimsSync, jwtService security.JWTService, emailSender email.Sender, audit ports.AuditLogger, cfg AuthServiceConfig) ports.AuthService {
 	return &AuthService{
 		AuthRepo: authRepo,
+		TokenRepo: tokenRepo,
+		SessionRepo: sessionRepo,
+		RoleRepo: roleRepo,
+		Revocations: revocations,
+		Attempts: attempts,
+		MFARepo: mfaRepo,
+		FirebaseVerifier: firebaseVerifier,
+		FirebaseClaims: firebaseClaims,
 		JWTService: jwtService,
 		EmailSender: emailSender,
+		Audit: audit,
+		Config: cfg,
 	}
 }
 
 // Register handles user registration, including password hashing and storage.
-func (s *AuthService) Register(ctx context.Context, req domain.RegisterRequest) (*domain.AuthResponse, error) {
+func (s *AuthService) Register(ctx context.Context, req domain.RegisterRequest) (resp *domain.AuthResponse, err error) {
+	defer func() { s.auditSignIn(ctx, domain.AuditEventRegister, req.Email, resp, err, nil) }()
+
 	// 1. Check if user already exists
 	existingUser, err := s.AuthRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
@@ -41,9 +100,12 @@ func (s *AuthService) Register(ctx context.Context, req domain.RegisterRequest)
 	if existingUser != nil {
 		return nil, errors.New("a user with this email already exists")
 	}
+	if err := s.checkPasswordPolicy("password", req.Password, req.Name, req.Email); err != nil {
+		return nil, err
+	}
 
 	// 2. Hash the password
-	hashedPassword, err := security.HashPassword(req.Password)
+	hashedPassword, err := security.HashPassword(req.Password, s.Config.PasswordHash)
 	if err != nil {
 		return nil, errors.New("failed to hash password")
 	}
@@ -59,55 +121,308 @@ func (s *AuthService) Register(ctx context.Context, req domain.RegisterRequest)
 		UpdatedAt: time.Now(),
 	}
 
-	// 4. Save the user to the database
+	// 4. Save the user with the default role to the database
 	if err := s.AuthRepo.CreateUser(ctx, newUser); err != nil {
 		return nil, fmt.Errorf("failed to save new user: %w", err)
 	}
 
-	// 5. Generate JWT token
-	token, err := s.JWTService.GenerateToken(newUser.ID, newUser.Email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate auth token: %w", err)
+	// 5. Send the verification email. A delivery failure does not fail the registration;
+	// the user can request a new token through /auth/resend-verification.
+	if err := s.sendVerificationEmail(ctx, &newUser); err != nil {
+		log.Printf("ERROR: Failed to send verification email to %s: %v", newUser.Email, err)
 	}
 
-	// 6. Return success response
-	return &domain.AuthResponse{
-		UserID: newUser.ID,
-		Email:  newUser.Email,
-		Name:   newUser.Name,
-		Token:  token,
-	}, nil
+	// 6. Unverified accounts cannot sign in under the block_login policy, so no tokens are issued yet
+	if s.Config.EmailVerificationPolicy == domain.VerificationPolicyBlockLogin {
+		return &domain.AuthResponse{
+			UserID:     newUser.ID,
+			Email:      newUser.Email,
+			Name:       newUser.Name,
+			IsVerified: newUser.IsVerified,
+		}, nil
+	}
+
+	// 7. Start a session and issue the access and refresh tokens
+	return s.startSession(ctx, &newUser)
 }
 
 // Login verifies user credentials and issues an authentication token.
-func (s *AuthService) Login(ctx context.Context, req domain.LoginRequest) (*domain.AuthResponse, error) {
-	// 1. Retrieve user by email
+func (s *AuthService) Login(ctx context.Context, req domain.LoginRequest) (resp *domain.AuthResponse, err error) {
+	defer func() {
+		s.auditSignIn(ctx, domain.AuditEventLogin, req.Email, resp, err, map[string]string{"method": "password"})
+	}()
+
+	// 1. Reject the attempt early if the account or the client IP is locked out
+	attemptKeys := s.limiter().attemptKeys(ctx, "login", req.Email)
+	if err := s.limiter().checkLockout(ctx, attemptKeys); err != nil {
+		return nil, err
+	}
+
+	// 2. Retrieve user by email
 	user, err := s.AuthRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, errors.New("internal error during login")
 	}
 	if user == nil {
+		// Unknown emails count as failures too, so lockouts do not reveal which accounts exist
+		s.limiter().recordFailure(ctx, attemptKeys)
 		return nil, errors.New("invalid credentials") // Use generic message for security
 	}
 
-	// 2. Compare the stored hash with the provided password
+	// 3. Compare the stored hash with the provided password
 	if err := security.CheckPasswordHash(req.Password, user.HashedPassword); err != nil {
+		s.limiter().recordFailure(ctx, attemptKeys)
 		return nil, errors.New("invalid credentials")
 	}
-	
-	// 3. Generate JWT token
-	token, err := s.JWTService.GenerateToken(user.ID, user.Email)
+	s.limiter().clearFailures(ctx, attemptKeys)
+
+	// Upgrade bcrypt hashes, and hashes with outdated parameters, now that the password is known
+	s.rehashPassword(ctx, user, req.Password)
+
+	// 4. Apply the verification policy and the second factor, then issue the tokens
+	return s.completeLogin(ctx, user)
+}
+
+// checkPasswordPolicy returns a *domain.ValidationError for the given request field
+// if the password breaks the policy. name and email are the user's own, which it must not contain.
+func (s *AuthService) checkPasswordPolicy(field string, password string, name string, email string) error {
+	violations := s.Config.PasswordPolicy.Check(password, name, email)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	validationErr := &domain.ValidationError{}
+	for _, violation := range violations {
+		validationErr.Fields = append(validationErr.Fields, domain.FieldError{
+			Field:   field,
+			Code:    violation.Code,
+			Message: violation.Message,
+		})
+	}
+	return validationErr
+}
+
+// rehashPassword replaces a bcrypt hash, or an Argon2id hash with outdated parameters, with a hash
+// using the current settings. Failures are only logged: the old hash keeps working.
+func (s *AuthService) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	if !security.NeedsRehash(user.HashedPassword, s.Config.PasswordHash) {
+		return
+	}
+
+	newHashedPassword, err := security.HashPassword(password, s.Config.PasswordHash)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate auth token: %w", err)
+		log.Printf("ERROR: Failed to rehash password of user %s: %v", user.ID, err)
+		return
+	}
+	if err := s.AuthRepo.ReplacePasswordHash(ctx, user.ID, user.HashedPassword, newHashedPassword); err != nil {
+		log.Printf("ERROR: Failed to store rehashed password of user %s: %v", user.ID, err)
+		return
 	}
+	user.HashedPassword = newHashedPassword
+}
 
-	// 4. Return success response
-	return &domain.AuthResponse{
-		UserID: user.ID,
-		Email:  user.Email,
-		Name:   user.Name,
-		Token:  token,
-	}, nil
+// ExchangeFirebaseToken signs in with a Firebase ID token. The matching local user is found by
+// Firebase UID or verified email (and linked), or created, so every sign-in method yields the same user ID.
+func (s *AuthService) ExchangeFirebaseToken(ctx context.Context, req domain.FirebaseExchangeRequest) (resp *domain.AuthResponse, err error) {
+	defer func() {
+		s.auditSignIn(ctx, domain.AuditEventLogin, "", resp, err, map[string]string{"method": "firebase"})
+	}()
+
+	if s.FirebaseVerifier == nil {
+		return nil, errors.New("firebase sign-in is not configured")
+	}
+
+	// 1. Verify the Firebase ID token
+	identity, err := s.FirebaseVerifier.VerifyIDToken(ctx, req.IDToken)
+	if err != nil {
+		return nil, errors.New("invalid firebase token")
+	}
+
+	// 2. Find or create the local user
+	user, err := s.findOrCreateFirebaseUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Keep the roles in the Firebase custom claims in line with ours
+	s.syncFirebaseRoles(ctx, user, identity)
+
+	// 4. Apply the verification policy and the second factor, then issue the tokens
+	return s.completeLogin(ctx, user)
+}
+
+// syncFirebaseRoles rewrites the user's Firebase custom claims if the ID token carried different
+// roles (organization memberships are pushed whenever they change). Failures are only logged; the local token is authoritative either way.
+func (s *AuthService) syncFirebaseRoles(ctx context.Context, user *domain.User, identity *domain.FirebaseIdentity) {
+	if s.FirebaseClaims == nil {
+		return
+	}
+
+	roles, err := s.RoleRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to load roles of user %s: %v", user.ID, err)
+		return
+	}
+	if slices.Equal(roles, identity.Roles) {
+		return
+	}
+
+	if err := s.FirebaseClaims.SyncUser(ctx, user.ID); err != nil {
+		log.Printf("ERROR: %v", err)
+	}
+}
+
+// findOrCreateFirebaseUser resolves a Firebase identity to a local user.
+func (s *AuthService) findOrCreateFirebaseUser(ctx context.Context, identity *domain.FirebaseIdentity) (*domain.User, error) {
+	// 1. Already linked by UID
+	user, err := s.AuthRepo.GetUserByFirebaseUID(ctx, identity.UID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during lookup: %w", err)
+	}
+	if user != nil {
+		return user, nil
+	}
+
+	// 2. Without an email there is nothing to match or create an account with
+	if identity.Email == "" {
+		return nil, errors.New("firebase account has no email address")
+	}
+
+	user, err = s.AuthRepo.GetUserByEmail(ctx, identity.Email)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during lookup: %w", err)
+	}
+
+	if user != nil {
+		// 3. Existing local account: only link it if Firebase has verified the email,
+		// otherwise anyone could claim an account by creating a Firebase user with that address
+		if !identity.EmailVerified {
+			return nil, errors.New("firebase email is not verified")
+		}
+		if err := s.AuthRepo.LinkFirebaseUID(ctx, user.ID, identity.UID); err != nil {
+			return nil, err
+		}
+		user.FirebaseUID = identity.UID
+
+		if !user.IsVerified {
+			if err := s.AuthRepo.MarkUserVerified(ctx, user.ID); err != nil {
+				return nil, fmt.Errorf("failed to mark user as verified: %w", err)
+			}
+			user.IsVerified = true
+		}
+		return user, nil
+	}
+
+	// 4. New account without a local password (one can be set through the forgot-password flow)
+	name := identity.Name
+	if name == "" {
+		name = strings.Split(identity.Email, "@")[0]
+	}
+	newUser := domain.User{
+		ID:          uuid.New(),
+		Name:        name,
+		Email:       identity.Email,
+		FirebaseUID: identity.UID,
+		IsVerified:  identity.EmailVerified,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := s.AuthRepo.CreateUser(ctx, newUser); err != nil {
+		return nil, fmt.Errorf("failed to save new user: %w", err)
+	}
+
+	log.Printf("Created user %s from Firebase account %s (%s)", newUser.ID, identity.UID, identity.SignInProvider)
+	return &newUser, nil
+}
+
+// completeLogin runs the checks shared by every sign-in method once the user is authenticated:
+// the email verification policy and, if enabled, the second factor. It then issues the tokens.
+func (s *AuthService) completeLogin(ctx context.Context, user *domain.User) (*domain.AuthResponse, error) {
+	// 1. Refuse disabled accounts, and unverified accounts if the deployment requires it
+	if user.IsDisabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+	if !user.IsVerified && s.Config.EmailVerificationPolicy == domain.VerificationPolicyBlockLogin {
+		return nil, errors.New("email not verified")
+	}
+
+	// 2. With two-factor authentication, only return a challenge; tokens follow at /auth/login/mfa
+	enrollment, err := s.MFARepo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, errors.New("internal error during login")
+	}
+	if enrollment.IsEnabled() {
+		mfaToken, expiresAt, err := s.JWTService.GenerateChallengeToken(user.ID, mfaLoginPurpose, s.Config.MFAChallengeTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate MFA challenge: %w", err)
+		}
+		return &domain.AuthResponse{
+			UserID:            user.ID,
+			Email:             user.Email,
+			Name:              user.Name,
+			IsVerified:        user.IsVerified,
+			MFARequired:       true,
+			MFAToken:          mfaToken,
+			MFATokenExpiresAt: expiresAt,
+		}, nil
+	}
+
+	// 3. Start a session and issue the access and refresh tokens
+	return s.startSession(ctx, user)
+}
+
+// CompleteMFALogin finishes a login that returned mfa_required, using a TOTP code or a recovery code.
+func (s *AuthService) CompleteMFALogin(ctx context.Context, req domain.MFALoginRequest) (resp *domain.AuthResponse, err error) {
+	defer func() {
+		s.auditSignIn(ctx, domain.AuditEventLogin, "", resp, err, map[string]string{"method": "mfa"})
+	}()
+
+	// 1. The challenge token proves the password step succeeded
+	challenge, err := s.JWTService.ValidateChallengeToken(req.MFAToken, mfaLoginPurpose)
+	if err != nil {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+
+	user, err := s.AuthRepo.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, errors.New("internal error during login")
+	}
+	if user == nil {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+
+	// 2. Second factor guesses share the brute-force protection of the login
+	attemptKeys := s.limiter().attemptKeys(ctx, "mfa", user.Email)
+	if err := s.limiter().checkLockout(ctx, attemptKeys); err != nil {
+		return nil, err
+	}
+
+	// 3. Verify the code against the active enrollment
+	enrollment, err := s.MFARepo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, errors.New("internal error during login")
+	}
+	if !enrollment.IsEnabled() {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+	if err := verifySecondFactor(ctx, s.MFARepo, enrollment, s.Config.MFA, req.Code, req.RecoveryCode); err != nil {
+		s.limiter().recordFailure(ctx, attemptKeys)
+		return nil, err
+	}
+	s.limiter().clearFailures(ctx, attemptKeys)
+
+	// 4. Use up the challenge, so a captured token cannot be replayed with a later code.
+	// It is only consumed after a correct code, so a typo does not force a new password step.
+	fresh, err := s.Revocations.ConsumeToken(ctx, challenge.ID, user.ID, challenge.ExpiresAt.Time)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume MFA token: %w", err)
+	}
+	if !fresh {
+		return nil, errors.New("invalid or expired MFA token")
+	}
+
+	// 5. Start a session and issue the access and refresh tokens
+	return s.startSession(ctx, user)
 }
 
 // StartPasswordReset initiates the forgot password flow by generating and saving a reset code.
@@ -120,17 +435,28 @@ func (s *AuthService) StartPasswordReset(ctx context.Context, req domain.ForgotP
 	if user == nil {
 		// IMPORTANT: For security, we return success even if the user doesn't exist.
 		log.Printf("Password reset requested for non-existent email: %s", req.Email)
+		recordAudit(ctx, s.Audit, domain.AuditEvent{
+			Type:    domain.AuditEventPasswordResetRequested,
+			Outcome: domain.AuditOutcomeFailure,
+			Email:   req.Email,
+			Details: map[string]string{"error": "user not found"},
+		})
 		return "", nil 
 	}
 	
 	// 2. Generate a secure, 6-digit random code
 	code := security.GenerateNumericCode(6)
 	
-	// 3. Save the code and its expiration time to the repository (password_reset_tokens table)
-	if err := s.AuthRepo.CreatePasswordResetCode(ctx, req.Email, code); err != nil {
+	// 3. Save only the keyed hash of the code and its expiration time (password_reset_tokens table)
+	if err := s.AuthRepo.CreatePasswordResetCode(ctx, req.Email, security.HashCode(s.Config.CodeHashKey, code)); err != nil {
 		return "", fmt.Errorf("failed to save reset code: %w", err)
 	}
 
+	// The actor is unknown: anyone can ask for a reset code, the subject is the account it is for
+	event := auditResult(domain.AuditEventPasswordResetRequested, uuid.Nil, user.ID, nil, nil)
+	event.Email = req.Email
+	recordAudit(ctx, s.Audit, event)
+
 	// 4. CRITICAL FIX: Call the actual email sender here
 	if err := s.EmailSender.SendPasswordResetCode(req.Email, code); err != nil {
 		// Log the error but return success to avoid leaking internal email failures
@@ -141,50 +467,319 @@ func (s *AuthService) StartPasswordReset(ctx context.Context, req domain.ForgotP
 	return code, nil // Return the generated code for testing
 }
 
-// ResetPassword validates the code and updates the user's password.
-func (s *AuthService) ResetPassword(ctx context.Context, req domain.ResetPasswordRequest) (*domain.AuthResponse, error) {
-	// 1. Check if the reset code is valid and not expired
-	if err := s.AuthRepo.VerifyPasswordResetCode(ctx, req.Email, req.Code); err != nil {
-		return nil, err // Returns specific errors like "code expired" or "invalid code"
+// ResetPassword validates the code, updates the user's password, revokes every session and token
+// and signs them in through completeLogin.
+func (s *AuthService) ResetPassword(ctx context.Context, req domain.ResetPasswordRequest) (resp *domain.AuthResponse, err error) {
+	defer func() { s.auditSignIn(ctx, domain.AuditEventPasswordResetCompleted, req.Email, resp, err, nil) }()
+
+	// 1. Reject the attempt early if the account or the client IP is locked out
+	attemptKeys := s.limiter().attemptKeys(ctx, "reset", req.Email)
+	if err := s.limiter().checkLockout(ctx, attemptKeys); err != nil {
+		return nil, err
 	}
-	
-	// 2. Retrieve user to get the User ID
+
+	// 2. Retrieve the user and check the new password against the policy before the code is
+	// used up, so a rejected password can be corrected without requesting a new code
 	user, err := s.AuthRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, errors.New("internal error retrieving user")
 	}
+	name := ""
+	if user != nil {
+		name = user.Name
+	}
+	if err := s.checkPasswordPolicy("new_password", req.NewPassword, name, req.Email); err != nil {
+		return nil, err
+	}
+
+	// 3. Check and consume the reset code in one step, so it cannot be used twice
+	// (the code is also invalidated after too many wrong guesses)
+	codeHash := security.HashCode(s.Config.CodeHashKey, req.Code)
+	if err := s.AuthRepo.ConsumePasswordResetCode(ctx, req.Email, codeHash, s.Config.Lockout.MaxResetCodeAttempts); err != nil {
+		s.limiter().recordFailure(ctx, attemptKeys)
+		return nil, err // Returns specific errors like "code expired" or "invalid code"
+	}
+	s.limiter().clearFailures(ctx, attemptKeys)
 	if user == nil {
 		return nil, errors.New("user not found after code verification")
 	}
 
-	// 3. Hash the new password
-	newHashedPassword, err := security.HashPassword(req.NewPassword)
+	// 4. Hash the new password
+	newHashedPassword, err := security.HashPassword(req.NewPassword, s.Config.PasswordHash)
 	if err != nil {
 		return nil, errors.New("failed to hash new password")
 	}
 
-	// 4. Update the user's password in the database
+	// 5. Update the user's password in the database
 	if err := s.AuthRepo.UpdateUserPassword(ctx, user.ID.String(), newHashedPassword); err != nil {
 		return nil, fmt.Errorf("failed to update password: %w", err)
 	}
 
-	// 5. Clean up: Delete the reset code
-	if err := s.AuthRepo.DeletePasswordResetCode(ctx, req.Email); err != nil {
-		log.Printf("Warning: Failed to delete reset code for %s: %v", req.Email, err)
+	// 6. Sign out every device, since whoever knew the old password may still hold a session
+	now := time.Now()
+	if err := s.revokeAllTokens(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+	// The cut-off also covers access tokens issued in its second, so the new ones must come later
+	time.Sleep(time.Until(now.Truncate(time.Second).Add(time.Second)))
+
+	// 7. Sign the user in like a login, so an enabled second factor is still required
+	return s.completeLogin(ctx, user)
+}
+
+// RefreshToken exchanges a refresh token for a new access/refresh token pair.
+// Each refresh token can only be used once; presenting a used token revokes the whole family.
+func (s *AuthService) RefreshToken(ctx context.Context, req domain.RefreshTokenRequest) (*domain.AuthResponse, error) {
+	// 1. Look up the token by its hash
+	stored, err := s.TokenRepo.GetRefreshTokenByHash(ctx, security.HashToken(req.RefreshToken))
+	if err != nil {
+		return nil, fmt.Errorf("repository error during refresh token lookup: %w", err)
+	}
+	if stored == nil || stored.RevokedAt != nil || stored.ExpiresAt.Before(time.Now()) {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// 2. Reuse detection: an already rotated token means the family is compromised
+	if stored.UsedAt != nil {
+		return nil, s.revokeFamilyOnReuse(ctx, stored)
+	}
+
+	// 3. Load the owner so the new access token carries current user data
+	user, err := s.AuthRepo.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, errors.New("internal error retrieving user")
+	}
+	if user == nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	if user.IsDisabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+	if !user.IsVerified && s.Config.EmailVerificationPolicy == domain.VerificationPolicyBlockLogin {
+		return nil, errors.New("email not verified")
+	}
+
+	// 4. Generate the replacement refresh token in the same family and rotate atomically
+	next, rawRefreshToken, err := s.newRefreshToken(user.ID, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.TokenRepo.RotateRefreshToken(ctx, stored.ID, *next); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			// Lost a race against another request using the same token
+			return nil, s.revokeFamilyOnReuse(ctx, stored)
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	// 5. Record the activity on the session
+	if err := s.SessionRepo.TouchSession(ctx, stored.FamilyID, 0); err != nil {
+		log.Printf("ERROR: Failed to update session %s: %v", stored.FamilyID, err)
 	}
 
-	// 6. Generate a new JWT token for the user
-	token, err := s.JWTService.GenerateToken(user.ID, user.Email)
+	// 6. Generate the new access token
+	return s.buildAuthResponse(ctx, user, stored.FamilyID, rawRefreshToken, next.ExpiresAt)
+}
+
+// VerifyEmail consumes a verification token and marks the owning account as verified.
+func (s *AuthService) VerifyEmail(ctx context.Context, req domain.VerifyEmailRequest) error {
+	// 1. Consume the token (single use, must not be expired)
+	userID, err := s.AuthRepo.ConsumeEmailVerificationToken(ctx, security.HashToken(req.Token))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate auth token after reset: %w", err)
+		return fmt.Errorf("repository error during token lookup: %w", err)
+	}
+	if userID == uuid.Nil {
+		return errors.New("invalid or expired verification token")
 	}
 
+	// 2. Mark the user as verified
+	if err := s.AuthRepo.MarkUserVerified(ctx, userID); err != nil {
+		return fmt.Errorf("failed to mark user as verified: %w", err)
+	}
+
+	return nil
+}
+
+// ResendVerification issues a new verification token. Like the password reset flow,
+// it reports success even if the email is unknown or already verified.
+func (s *AuthService) ResendVerification(ctx context.Context, req domain.ResendVerificationRequest) error {
+	user, err := s.AuthRepo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		return errors.New("internal server error")
+	}
+	if user == nil || user.IsVerified {
+		log.Printf("Verification resend skipped for %s (unknown or already verified)", req.Email)
+		return nil
+	}
+
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		// Log the error but return success to avoid leaking internal email failures
+		log.Printf("ERROR: Failed to send verification email to %s: %v", req.Email, err)
+	}
+	return nil
+}
+
+// sendVerificationEmail stores a new verification token for the user and emails it.
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *domain.User) error {
+	token, err := security.GenerateOpaqueToken(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.Config.EmailVerificationTTL)
+	if err := s.AuthRepo.ReplaceEmailVerificationToken(ctx, user.ID, security.HashToken(token), expiresAt); err != nil {
+		return fmt.Errorf("failed to save verification token: %w", err)
+	}
+
+	link := ""
+	if s.Config.EmailVerificationURL != "" {
+		if link, err = tokenLink(s.Config.EmailVerificationURL, token); err != nil {
+			return fmt.Errorf("failed to build verification link: %w", err)
+		}
+	}
+
+	return s.EmailSender.SendVerificationEmail(user.Email, token, link)
+}
+
+// Logout revokes the access token of the current request and the session (refresh token family) it belongs to.
+func (s *AuthService) Logout(ctx context.Context, identity domain.TokenIdentity) (err error) {
+	defer func() {
+		details := map[string]string{"session_id": identity.SessionID.String()}
+		recordAudit(ctx, s.Audit, auditResult(domain.AuditEventLogout, identity.UserID, identity.UserID, err, details))
+	}()
+
+	// 1. Revoke the access token itself so it stops working immediately
+	if err := s.Revocations.RevokeToken(ctx, identity.TokenID, identity.UserID, identity.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to revoke access token: %w", err)
+	}
+
+	// 2. Revoke the session and its refresh tokens so it cannot be renewed
+	if identity.SessionID != uuid.Nil {
+		if _, err := s.SessionRepo.RevokeSession(ctx, identity.UserID, identity.SessionID); err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// LogoutAll revokes every access and refresh token of the user, signing them out on all devices.
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID) (err error) {
+	defer func() { recordAudit(ctx, s.Audit, auditResult(domain.AuditEventLogoutAll, userID, userID, err, nil)) }()
+
+	return s.revokeAllTokens(ctx, userID, time.Now())
+}
+
+// revokeAllTokens signs the user out on all devices: it revokes every session with its refresh tokens,
+// and every access token issued up to now or in the same second.
+func (s *AuthService) revokeAllTokens(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	// 1. Revoke all sessions and their refresh tokens so none can be renewed
+	if err := s.SessionRepo.RevokeAllSessions(ctx, userID, uuid.Nil); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	// 2. Revoke every access token issued up to now. Those tokens all expire within one access token TTL.
+	if err := s.Revocations.RevokeAllForUser(ctx, userID, now, now.Add(s.Config.AccessTokenTTL)); err != nil {
+		return fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+
+	return nil
+}
+
+// revokeFamilyOnReuse revokes the session and every token descending from the same login and returns the reuse error.
+func (s *AuthService) revokeFamilyOnReuse(ctx context.Context, token *domain.RefreshToken) error {
+	log.Printf("SECURITY: Refresh token reuse detected for user %s (family %s). Revoking family.", token.UserID, token.FamilyID)
+	if err := s.TokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+	if _, err := s.SessionRepo.RevokeSession(ctx, token.UserID, token.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return domain.ErrRefreshTokenReused
+}
+
+// startSession records a new session for the signing-in device and issues the first token pair of its family.
+func (s *AuthService) startSession(ctx context.Context, user *domain.User) (*domain.AuthResponse, error) {
+	// Every sign-in path ends here, so this is the last line of defence for disabled accounts
+	if user.IsDisabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	client := domain.ClientInfoFromContext(ctx)
+	now := time.Now()
+	session := domain.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := s.SessionRepo.CreateSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+
+	return s.issueTokens(ctx, user, session.ID)
+}
+
+// issueTokens creates and stores a new refresh token in the given family and returns a full token pair.
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, familyID uuid.UUID) (*domain.AuthResponse, error) {
+	refreshToken, rawRefreshToken, err := s.newRefreshToken(user.ID, familyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.TokenRepo.CreateRefreshToken(ctx, *refreshToken); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return s.buildAuthResponse(ctx, user, familyID, rawRefreshToken, refreshToken.ExpiresAt)
+}
+
+// newRefreshToken generates a random refresh token. It returns the record to store and the raw value for the client.
+func (s *AuthService) newRefreshToken(userID uuid.UUID, familyID uuid.UUID) (*domain.RefreshToken, string, error) {
+	raw, err := security.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	now := time.Now()
+	return &domain.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: security.HashToken(raw),
+		ExpiresAt: now.Add(s.Config.RefreshTokenTTL),
+		CreatedAt: now,
+	}, raw, nil
+}
+
+// buildAuthResponse generates the JWT access token and assembles the response returned to the client.
+// The user's current roles are loaded here, so a refresh picks up role changes.
+func (s *AuthService) buildAuthResponse(ctx context.Context, user *domain.User, sessionID uuid.UUID, refreshToken string, refreshExpiresAt time.Time) (*domain.AuthResponse, error) {
+	roles, err := s.RoleRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user roles: %w", err)
+	}
+
+	token, expiresAt, err := s.JWTService.GenerateToken(security.TokenSubject{
+		UserID:    user.ID,
+		Email:     user.Email,
+		SessionID: sessionID,
+		EmailVerified: user.IsVerified,
+		Roles:     roles,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate auth token: %w", err)
+	}
 
-	// 7. Return success response
 	return &domain.AuthResponse{
-		UserID: user.ID,
-		Email:  user.Email,
-		Name:   user.Name,
-		Token:  token,
+		UserID:                user.ID,
+		Email:                 user.Email,
+		Name:                  user.Name,
+		IsVerified:            user.IsVerified,
+		Token:                 token,
+		ExpiresAt:             expiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshExpiresAt,
 	}, nil
 }
\ No newline at end of file
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// firebaseSyncBatchSize is the number of users loaded per query while re-syncing every user.
+const firebaseSyncBatchSize = 500
+
+// FirebaseClaimsService is the concrete implementation of the ports.FirebaseClaimsSync interface.
+type FirebaseClaimsService struct {
+	AuthRepo ports.AuthRepository
+	RoleRepo ports.RoleRepository
+	Claims   ports.FirebaseClaimsUpdater
+}
+
+// NewFirebaseClaimsService creates a new instance of the FirebaseClaimsService.
+func NewFirebaseClaimsService(authRepo ports.AuthRepository, roleRepo ports.RoleRepository, claims ports.FirebaseClaimsUpdater) ports.FirebaseClaimsSync {
+	return &FirebaseClaimsService{
+		AuthRepo: authRepo,
+		RoleRepo: roleRepo,
+		Claims:   claims,
+	}
+}
+
+// SyncUser loads the user and writes their current claims to Firebase.
+func (s *FirebaseClaimsService) SyncUser(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.AuthRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load user %s: %w", userID, err)
+	}
+	if user == nil || user.FirebaseUID == "" {
+		return nil
+	}
+	return s.syncUser(ctx, user)
+}
+
+// syncUser writes the claims of a loaded, linked user. A disabled user gets no roles,
+// so Firebase tokens they still hold stop granting access once they are refreshed.
+func (s *FirebaseClaimsService) syncUser(ctx context.Context, user *domain.User) error {
+	var roles []string
+	if !user.IsDisabled() {
+		var err error
+		roles, err = s.RoleRepo.GetUserRoles(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load roles of user %s: %w", user.ID, err)
+		}
+	}
+
+	claims := domain.FirebaseCustomClaims(roles)
+	if err := s.Claims.SetCustomClaims(ctx, user.FirebaseUID, claims); err != nil {
+		return fmt.Errorf("failed to set Firebase custom claims for %s: %w", user.FirebaseUID, err)
+	}
+	return nil
+}
+
+// SyncAll walks the linked users in ID order. A failing user is logged and counted, and the walk
+// continues; only errors loading the users themselves stop it.
+func (s *FirebaseClaimsService) SyncAll(ctx context.Context) (*domain.FirebaseClaimsSyncReport, error) {
+	report := &domain.FirebaseClaimsSyncReport{}
+	afterID := uuid.Nil
+
+	for {
+		users, err := s.AuthRepo.ListFirebaseUsers(ctx, afterID, firebaseSyncBatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to load users after %s: %w", afterID, err)
+		}
+
+		for i := range users {
+			afterID = users[i].ID
+			if err := s.syncUser(ctx, &users[i]); err != nil {
+				log.Printf("ERROR: %v", err)
+				report.Failed++
+				continue
+			}
+			report.Synced++
+		}
+
+		if len(users) < firebaseSyncBatchSize {
+			return report, nil
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+	fbclient "github.com/mitcheltastic/ManproBackend/internal/infrastructure/firebase"
+)
+
+// fakeAuthClient is an in-memory fbclient.AuthClient that records the custom claims it is given.
+type fakeAuthClient struct {
+	claims  map[string]map[string]interface{}
+	failUID string
+}
+
+func newFakeAuthClient() *fakeAuthClient {
+	return &fakeAuthClient{claims: map[string]map[string]interface{}{}}
+}
+
+func (f *fakeAuthClient) VerifyIDToken(ctx context.Context, idToken string) (*auth.Token, error) {
+	return nil, errors.New("not supported by the fake")
+}
+
+func (f *fakeAuthClient) SetCustomUserClaims(ctx context.Context, uid string, customClaims map[string]interface{}) error {
+	if uid == f.failUID {
+		return errors.New("firebase unavailable")
+	}
+	f.claims[uid] = customClaims
+	return nil
+}
+
+// claimsUsers is an in-memory ports.AuthRepository serving the lookups of the claims sync.
+type claimsUsers struct {
+	ports.AuthRepository
+	users []domain.User
+}
+
+func (r *claimsUsers) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	for i := range r.users {
+		if r.users[i].ID == id {
+			user := r.users[i]
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *claimsUsers) ListFirebaseUsers(ctx context.Context, afterID uuid.UUID, limit int) ([]domain.User, error) {
+	linked := []domain.User{}
+	for _, user := range r.users {
+		if user.FirebaseUID != "" && bytes.Compare(user.ID[:], afterID[:]) > 0 {
+			linked = append(linked, user)
+		}
+	}
+	sort.Slice(linked, func(i, j int) bool { return bytes.Compare(linked[i].ID[:], linked[j].ID[:]) < 0 })
+	if len(linked) > limit {
+		linked = linked[:limit]
+	}
+	return linked, nil
+}
+
+// claimsRoles is an in-memory ports.RoleRepository.
+type claimsRoles struct {
+	ports.RoleRepository
+	roles map[uuid.UUID][]string
+}
+
+func (r *claimsRoles) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	return r.roles[userID], nil
+}
+
+func newClaimsService(users []domain.User, roles map[uuid.UUID][]string) (ports.FirebaseClaimsSync, *fakeAuthClient) {
+	fake := newFakeAuthClient()
+	svc := NewFirebaseClaimsService(
+		&claimsUsers{users: users},
+		&claimsRoles{roles: roles},
+		&fbclient.Client{AuthClient: fake},
+	)
+	return svc, fake
+}
+
+func TestSyncUserWritesRoles(t *testing.T) {
+	user := domain.User{ID: uuid.New(), FirebaseUID: "fb-linked"}
+	svc, fake := newClaimsService([]domain.User{user}, map[uuid.UUID][]string{user.ID: {"member", "manager"}})
+
+	if err := svc.SyncUser(context.Background(), user.ID); err != nil {
+		t.Fatalf("SyncUser: %v", err)
+	}
+
+	claims, ok := fake.claims["fb-linked"]
+	if !ok {
+		t.Fatal("no claims were written for the linked user")
+	}
+	if roles := claims[domain.FirebaseRolesClaim].([]string); !slices.Equal(roles, []string{"member", "manager"}) {
+		t.Errorf("roles claim = %v", roles)
+	}
+	if len(claims) != 1 {
+		t.Errorf("claims = %v, want only the roles claim", claims)
+	}
+}
+
+func TestSyncUserSkipsUnlinkedUsers(t *testing.T) {
+	user := domain.User{ID: uuid.New()}
+	svc, fake := newClaimsService([]domain.User{user}, map[uuid.UUID][]string{user.ID: {"member"}})
+
+	if err := svc.SyncUser(context.Background(), user.ID); err != nil {
+		t.Fatalf("SyncUser: %v", err)
+	}
+	if err := svc.SyncUser(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("SyncUser of an unknown user: %v", err)
+	}
+	if len(fake.claims) != 0 {
+		t.Errorf("claims were written for unlinked users: %v", fake.claims)
+	}
+}
+
+func TestSyncUserClearsClaimsOfDisabledUsers(t *testing.T) {
+	disabledAt := time.Now()
+	user := domain.User{ID: uuid.New(), FirebaseUID: "fb-disabled", DisabledAt: &disabledAt}
+	svc, fake := newClaimsService([]domain.User{user}, map[uuid.UUID][]string{user.ID: {"admin"}})
+
+	if err := svc.SyncUser(context.Background(), user.ID); err != nil {
+		t.Fatalf("SyncUser: %v", err)
+	}
+	if roles := fake.claims["fb-disabled"][domain.FirebaseRolesClaim].([]string); len(roles) != 0 {
+		t.Errorf("disabled user kept roles %v", roles)
+	}
+}
+
+func TestSyncAllReportsFailuresAndContinues(t *testing.T) {
+	users := []domain.User{
+		{ID: uuid.New(), FirebaseUID: "fb-1"},
+		{ID: uuid.New(), FirebaseUID: "fb-2"},
+		{ID: uuid.New(), FirebaseUID: "fb-broken"},
+		{ID: uuid.New(), FirebaseUID: "fb-3"},
+		{ID: uuid.New()}, // Not linked
+	}
+	roles := map[uuid.UUID][]string{}
+	for _, user := range users {
+		roles[user.ID] = []string{"member"}
+	}
+	svc, fake := newClaimsService(users, roles)
+	fake.failUID = "fb-broken"
+
+	report, err := svc.SyncAll(context.Background())
+	if err != nil {
+		t.Fatalf("SyncAll: %v", err)
+	}
+	if report.Synced != 3 || report.Failed != 1 {
+		t.Errorf("report = %+v, want 3 synced and 1 failed", *report)
+	}
+	for _, uid := range []string{"fb-1", "fb-2", "fb-3"} {
+		if _, ok := fake.claims[uid]; !ok {
+			t.Errorf("no claims were written for %s", uid)
+		}
+	}
+}
+
+func TestSyncAllPagesThroughEveryUser(t *testing.T) {
+	users := make([]domain.User, firebaseSyncBatchSize+2)
+	for i := range users {
+		users[i] = domain.User{ID: uuid.New(), FirebaseUID: uuid.NewString()}
+	}
+	svc, fake := newClaimsService(users, nil)
+
+	report, err := svc.SyncAll(context.Background())
+	if err != nil {
+		t.Fatalf("SyncAll: %v", err)
+	}
+	if report.Synced != len(users) || report.Failed != 0 || len(fake.claims) != len(users) {
+		t.Errorf("report = %+v with %d accounts written, want %d synced", *report, len(fake.claims), len(users))
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// LockoutConfig controls the brute-force protection of login and password reset.
+type LockoutConfig struct {
+	// MaxAttemptsPerAccount is the number of failures for one email before it is locked out.
+	MaxAttemptsPerAccount int
+	// MaxAttemptsPerIP is the number of failures from one IP (across all accounts) before it is locked out.
+	MaxAttemptsPerIP int
+	// Window is how long failures are remembered; a quiet period this long resets the counter.
+	Window time.Duration
+	// BaseDuration is the first lockout; each further failure doubles it up to MaxDuration.
+	BaseDuration time.Duration
+	MaxDuration  time.Duration
+	// MaxResetCodeAttempts is the number of wrong guesses after which a reset code is invalidated.
+	MaxResetCodeAttempts int
+}
+
+// attemptLimiter applies a LockoutConfig to an AttemptStore. It is shared by the services that
+// check secrets a client could guess (passwords, reset codes, second factors).
+type attemptLimiter struct {
+	attempts ports.AttemptStore
+	config   LockoutConfig
+}
+
+// limiter returns the attemptLimiter of the AuthService.
+func (s *AuthService) limiter() attemptLimiter {
+	return attemptLimiter{attempts: s.Attempts, config: s.Config.Lockout}
+}
+
+// attemptKey is a counter key together with the number of failures it tolerates.
+type attemptKey struct {
+	name        string
+	maxAttempts int
+}
+
+// attemptKeys returns the per-account and (if known) per-IP counter keys for an action such as "login".
+func (l attemptLimiter) attemptKeys(ctx context.Context, action string, email string) []attemptKey {
+	keys := []attemptKey{{
+		name:        fmt.Sprintf("%s:email:%s", action, strings.ToLower(email)),
+		maxAttempts: l.config.MaxAttemptsPerAccount,
+	}}
+
+	if ip := domain.ClientInfoFromContext(ctx).IP; ip != "" {
+		keys = append(keys, attemptKey{
+			name:        fmt.Sprintf("%s:ip:%s", action, ip),
+			maxAttempts: l.config.MaxAttemptsPerIP,
+		})
+	}
+	return keys
+}
+
+// ipAttemptKeys returns only the per-IP counter key, for actions whose requests carry no email address.
+func (l attemptLimiter) ipAttemptKeys(ctx context.Context, action string) []attemptKey {
+	ip := domain.ClientInfoFromContext(ctx).IP
+	if ip == "" {
+		return nil
+	}
+	return []attemptKey{{
+		name:        fmt.Sprintf("%s:ip:%s", action, ip),
+		maxAttempts: l.config.MaxAttemptsPerIP,
+	}}
+}
+
+// checkLockout returns a *domain.TooManyAttemptsError if any of the keys is currently locked out.
+func (l attemptLimiter) checkLockout(ctx context.Context, keys []attemptKey) error {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.name
+	}
+
+	lockedUntil, err := l.attempts.GetLockedUntil(ctx, names...)
+	if err != nil {
+		return fmt.Errorf("failed to check lockout: %w", err)
+	}
+
+	if remaining := time.Until(lockedUntil); remaining > 0 {
+		return &domain.TooManyAttemptsError{RetryAfter: remaining}
+	}
+	return nil
+}
+
+// recordFailure counts a failed attempt on every key and locks out the keys that crossed their limit.
+// The lockout doubles with every further failure: base, 2*base, 4*base, ... up to the maximum.
+func (l attemptLimiter) recordFailure(ctx context.Context, keys []attemptKey) {
+	cfg := l.config
+
+	for _, key := range keys {
+		failures, err := l.attempts.RecordFailure(ctx, key.name, cfg.Window)
+		if err != nil {
+			log.Printf("ERROR: Failed to record failed attempt for %s: %v", key.name, err)
+			continue
+		}
+		if failures < key.maxAttempts {
+			continue
+		}
+
+		lockout := cfg.BaseDuration
+		for i := key.maxAttempts; i < failures && lockout < cfg.MaxDuration; i++ {
+			lockout *= 2
+		}
+		if lockout > cfg.MaxDuration {
+			lockout = cfg.MaxDuration
+		}
+
+		log.Printf("SECURITY: %s locked out for %s after %d failed attempts", key.name, lockout, failures)
+		if err := l.attempts.SetLockout(ctx, key.name, time.Now().Add(lockout)); err != nil {
+			log.Printf("ERROR: Failed to set lockout for %s: %v", key.name, err)
+		}
+	}
+}
+
+// clearFailures resets the per-account counter after a successful attempt.
+// The per-IP counter is left alone so an attacker cannot reset it with an account of their own.
+func (l attemptLimiter) clearFailures(ctx context.Context, keys []attemptKey) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := l.attempts.ClearFailures(ctx, keys[0].name); err != nil {
+		log.Printf("ERROR: Failed to clear failed attempts for %s: %v", keys[0].name, err)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/pkg/security"
+)
+
+// errMagicLinkDisabled is returned by both magic link endpoints when the deployment has not enabled them.
+var errMagicLinkDisabled = errors.New("magic link login is not enabled")
+
+// StartMagicLinkLogin emails a single-use login link. Like the password reset flow, it reports
+// success even if the email is unknown, and every request counts against the reset-style rate limit.
+func (s *AuthService) StartMagicLinkLogin(ctx context.Context, req domain.MagicLinkRequest) (string, error) {
+	if !s.Config.MagicLinkEnabled {
+		return "", errMagicLinkDisabled
+	}
+
+	// 1. Throttle sends per email and per IP, so the endpoint cannot be used to flood a mailbox
+	attemptKeys := s.limiter().attemptKeys(ctx, "magic_link", req.Email)
+	if err := s.limiter().checkLockout(ctx, attemptKeys); err != nil {
+		return "", err
+	}
+	s.limiter().recordFailure(ctx, attemptKeys)
+
+	// 2. Check if the user exists (and may sign in)
+	user, err := s.AuthRepo.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		return "", errors.New("internal server error")
+	}
+	if user == nil || user.IsDisabled() {
+		// IMPORTANT: For security, we return success even if the account cannot sign in.
+		log.Printf("Magic link requested for unknown or disabled email: %s", req.Email)
+		recordAudit(ctx, s.Audit, domain.AuditEvent{
+			Type:    domain.AuditEventMagicLinkRequested,
+			Outcome: domain.AuditOutcomeFailure,
+			Email:   req.Email,
+			Details: map[string]string{"error": "user not found or disabled"},
+		})
+		return "", nil
+	}
+
+	// 3. Save only the keyed hash of the token; a new link invalidates the previous one
+	token, err := security.GenerateOpaqueToken(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate magic link token: %w", err)
+	}
+	expiresAt := time.Now().Add(s.Config.MagicLinkTTL)
+	if err := s.AuthRepo.ReplaceMagicLinkToken(ctx, user.ID, security.HashCode(s.Config.CodeHashKey, token), expiresAt); err != nil {
+		return "", fmt.Errorf("failed to save magic link token: %w", err)
+	}
+
+	event := auditResult(domain.AuditEventMagicLinkRequested, uuid.Nil, user.ID, nil, nil)
+	event.Email = req.Email
+	recordAudit(ctx, s.Audit, event)
+
+	// 4. Email the link (or the bare token if no frontend URL is configured)
+	link := ""
+	if s.Config.MagicLinkURL != "" {
+		if link, err = tokenLink(s.Config.MagicLinkURL, token); err != nil {
+			return "", fmt.Errorf("failed to build magic link: %w", err)
+		}
+	}
+	if err := s.EmailSender.SendMagicLink(user.Email, token, link); err != nil {
+		// Log the error but return success to avoid leaking internal email failures
+		log.Printf("ERROR: Failed to send magic link to %s: %v", user.Email, err)
+		return "", nil
+	}
+
+	return token, nil // Return the token for testing
+}
+
+// tokenLink adds the token to a frontend page URL as its "token" query parameter. Any query
+// the page URL already has is kept.
+func tokenLink(pageURL string, token string) (string, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// VerifyMagicLink consumes a magic link token and signs its owner in. Opening the link proves
+// ownership of the email address, so an unverified account becomes verified.
+func (s *AuthService) VerifyMagicLink(ctx context.Context, req domain.MagicLinkVerifyRequest) (resp *domain.AuthResponse, err error) {
+	defer func() {
+		s.auditSignIn(ctx, domain.AuditEventLogin, "", resp, err, map[string]string{"method": "magic_link"})
+	}()
+
+	if !s.Config.MagicLinkEnabled {
+		return nil, errMagicLinkDisabled
+	}
+
+	// 1. The request carries no email, so token guessing is only limited per IP
+	attemptKeys := s.limiter().ipAttemptKeys(ctx, "magic_link_verify")
+	if err := s.limiter().checkLockout(ctx, attemptKeys); err != nil {
+		return nil, err
+	}
+
+	// 2. Check and consume the token in one step, so it cannot be used twice
+	userID, err := s.AuthRepo.ConsumeMagicLinkToken(ctx, security.HashCode(s.Config.CodeHashKey, req.Token))
+	if err != nil {
+		return nil, fmt.Errorf("repository error during token lookup: %w", err)
+	}
+	if userID == uuid.Nil {
+		s.limiter().recordFailure(ctx, attemptKeys)
+		return nil, errors.New("invalid or expired magic link")
+	}
+
+	// 3. Load the user and mark the address as verified
+	user, err := s.AuthRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("internal error during login")
+	}
+	if user == nil {
+		return nil, errors.New("invalid or expired magic link")
+	}
+	if !user.IsVerified {
+		if err := s.AuthRepo.MarkUserVerified(ctx, user.ID); err != nil {
+			return nil, fmt.Errorf("failed to mark user as verified: %w", err)
+		}
+		user.IsVerified = true
+	}
+
+	// 4. Apply the disabled check and the second factor, then issue the tokens
+	return s.completeLogin(ctx, user)
+}
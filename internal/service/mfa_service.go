@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+	"github.com/mitcheltastic/ManproBackend/internal/pkg/security"
+)
+
+// recoveryCodeCount is the number of recovery codes generated when two-factor authentication is enabled.
+const recoveryCodeCount = 10
+
+// MFAConfig holds the settings shared by the MFA flows.
+type MFAConfig struct {
+	// Issuer is the account label shown in authenticator apps.
+	Issuer string
+	// EncryptionKey encrypts TOTP secrets at rest.
+	EncryptionKey []byte
+	// CodeHashKey hashes recovery codes (same key as reset codes).
+	CodeHashKey []byte
+}
+
+// MFAService is the concrete implementation of the ports.MFAService interface.
+type MFAService struct {
+	MFARepo  ports.MFARepository
+	Attempts ports.AttemptStore
+	Config   MFAConfig
+	// Lockout is the brute-force protection of the login, which also covers the disable flow.
+	Lockout LockoutConfig
+}
+
+// NewMFAService creates a new instance of the MFAService.
+func NewMFAService(mfaRepo ports.MFARepository, attempts ports.AttemptStore, cfg MFAConfig, lockout LockoutConfig) ports.MFAService {
+	return &MFAService{
+		MFARepo:  mfaRepo,
+		Attempts: attempts,
+		Config:   cfg,
+		Lockout:  lockout,
+	}
+}
+
+// EnrollTOTP generates a new secret and stores it as a pending enrollment.
+func (s *MFAService) EnrollTOTP(ctx context.Context, userID uuid.UUID, email string) (*domain.TOTPEnrollmentResponse, error) {
+	// 1. Refuse to overwrite an active authenticator (it must be disabled first)
+	existing, err := s.MFARepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during MFA lookup: %w", err)
+	}
+	if existing.IsEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	// 2. Generate the shared secret and store it encrypted
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+	encrypted, err := security.EncryptSecret(s.Config.EncryptionKey, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt TOTP secret: %w", err)
+	}
+	if err := s.MFARepo.SavePendingTOTP(ctx, userID, encrypted); err != nil {
+		return nil, fmt.Errorf("failed to save TOTP enrollment: %w", err)
+	}
+
+	// 3. Return the secret once so the client can show it as a QR code
+	return &domain.TOTPEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: security.TOTPURI(s.Config.Issuer, email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once the user proves the authenticator app works.
+func (s *MFAService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, req domain.ConfirmTOTPRequest) (*domain.RecoveryCodesResponse, error) {
+	// 1. Load the pending enrollment
+	enrollment, err := s.MFARepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during MFA lookup: %w", err)
+	}
+	if enrollment == nil {
+		return nil, errors.New("no pending two-factor enrollment")
+	}
+	if enrollment.IsEnabled() {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	// 2. Check the code against the pending secret
+	secret, err := security.DecryptSecret(s.Config.EncryptionKey, enrollment.SecretEncrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+	}
+	step, ok := security.ValidateTOTP(secret, req.Code, time.Now())
+	if !ok {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	// 3. Generate recovery codes; only their hashes are stored
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := security.GenerateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		codes[i] = code
+		hashes[i] = security.HashCode(s.Config.CodeHashKey, code)
+	}
+
+	// 4. Enable the enrollment and store the codes together
+	if err := s.MFARepo.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	return &domain.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP removes the authenticator after checking a current code or an unused recovery code.
+// Wrong codes count towards the same lockout as the second step of the login.
+func (s *MFAService) DisableTOTP(ctx context.Context, userID uuid.UUID, email string, req domain.DisableTOTPRequest) error {
+	limiter := attemptLimiter{attempts: s.Attempts, config: s.Lockout}
+	attemptKeys := limiter.attemptKeys(ctx, "mfa", email)
+	if err := limiter.checkLockout(ctx, attemptKeys); err != nil {
+		return err
+	}
+
+	enrollment, err := s.MFARepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("repository error during MFA lookup: %w", err)
+	}
+	if !enrollment.IsEnabled() {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	if err := verifySecondFactor(ctx, s.MFARepo, enrollment, s.Config, req.Code, req.RecoveryCode); err != nil {
+		limiter.recordFailure(ctx, attemptKeys)
+		return err
+	}
+	limiter.clearFailures(ctx, attemptKeys)
+
+	if err := s.MFARepo.DisableTOTP(ctx, userID); err != nil {
+		return fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+	return nil
+}
+
+// verifySecondFactor checks a TOTP code (rejecting replays) or consumes a recovery code.
+// It is shared by the MFA login step and the disable flow.
+func verifySecondFactor(ctx context.Context, repo ports.MFARepository, enrollment *domain.TOTPEnrollment, cfg MFAConfig, code string, recoveryCode string) error {
+	if code != "" {
+		secret, err := security.DecryptSecret(cfg.EncryptionKey, enrollment.SecretEncrypted)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt TOTP secret: %w", err)
+		}
+
+		step, ok := security.ValidateTOTP(secret, code, time.Now())
+		if !ok {
+			return errors.New("invalid two-factor code")
+		}
+
+		fresh, err := repo.UseTOTPStep(ctx, enrollment.UserID, step)
+		if err != nil {
+			return fmt.Errorf("failed to record TOTP use: %w", err)
+		}
+		if !fresh {
+			return errors.New("invalid two-factor code") // Replayed code
+		}
+		return nil
+	}
+
+	codeHash := security.HashCode(cfg.CodeHashKey, security.NormalizeRecoveryCode(recoveryCode))
+	consumed, err := repo.ConsumeRecoveryCode(ctx, enrollment.UserID, codeHash)
+	if err != nil {
+		return fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+	if !consumed {
+		return errors.New("invalid two-factor code")
+	}
+	return nil
+}
@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+	"github.com/mitcheltastic/ManproBackend/internal/pkg/email"
+	"github.com/mitcheltastic/ManproBackend/internal/pkg/security"
+)
+
+var (
+	// errOrganizationNotFound is also returned to non-members, so they cannot probe which organizations exist.
+	errOrganizationNotFound = errors.New("organization not found")
+	errOrgRoleInsufficient  = errors.New("insufficient organization role")
+	// errInvalidInvitation is returned for unknown, used and expired invitation tokens alike.
+	errInvalidInvitation = errors.New("invalid or expired invitation")
+)
+
+// OrganizationService is the concrete implementation of the ports.OrganizationService interface.
+type OrganizationService struct {
+	OrgRepo     ports.OrganizationRepository
+	AuthRepo    ports.AuthRepository
+	EmailSender email.Sender
+	Audit       ports.AuditLogger
+	Config      OrganizationServiceConfig
+}
+
+// OrganizationServiceConfig holds the tunable settings of the OrganizationService (loaded from config.Config).
+type OrganizationServiceConfig struct {
+	InvitationTTL time.Duration
+	// InvitationURL is the optional frontend page that receives the invitation token as ?token=...
+	// (added to any query it already has).
+	InvitationURL string
+}
+
+// NewOrganizationService creates a new instance of the OrganizationService.
+func NewOrganizationService(orgRepo ports.OrganizationRepository, authRepo ports.AuthRepository, emailSender email.Sender, audit ports.AuditLogger, cfg OrganizationServiceConfig) ports.OrganizationService {
+	return &OrganizationService{
+		OrgRepo:     orgRepo,
+		AuthRepo:    authRepo,
+		EmailSender: emailSender,
+		Audit:       audit,
+		Config:      cfg,
+	}
+}
+
+// CreateOrganization creates the organization with the caller as its first owner.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, actorID uuid.UUID, req domain.OrganizationRequest) (resp *domain.OrganizationMembership, err error) {
+	defer func() {
+		var orgID uuid.UUID
+		if resp != nil {
+			orgID = resp.ID
+		}
+		s.recordAudit(ctx, domain.AuditEventOrgCreated, actorID, actorID, orgID, err, map[string]string{"name": req.Name})
+	}()
+
+	name, err := organizationName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	org := domain.Organization{
+		ID:        uuid.New(),
+		Name:      name,
+		CreatedBy: actorID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.OrgRepo.CreateOrganization(ctx, org, actorID); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return &domain.OrganizationMembership{Organization: org, Role: domain.OrgRoleOwner}, nil
+}
+
+// ListOrganizations returns the caller's organizations.
+func (s *OrganizationService) ListOrganizations(ctx context.Context, actorID uuid.UUID) ([]domain.OrganizationMembership, error) {
+	orgs, err := s.OrgRepo.ListUserOrganizations(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during organization lookup: %w", err)
+	}
+	return orgs, nil
+}
+
+// GetOrganization returns the organization with the caller's role in it.
+func (s *OrganizationService) GetOrganization(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID) (*domain.OrganizationMembership, error) {
+	return s.requireRole(ctx, actorID, orgID, domain.OrgRoleMember)
+}
+
+// RenameOrganization changes the name; admins and owners only.
+func (s *OrganizationService) RenameOrganization(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, req domain.OrganizationRequest) (*domain.OrganizationMembership, error) {
+	membership, err := s.requireRole(ctx, actorID, orgID, domain.OrgRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := organizationName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	found, err := s.OrgRepo.RenameOrganization(ctx, orgID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename organization: %w", err)
+	}
+	if !found {
+		return nil, errOrganizationNotFound
+	}
+
+	membership.Name = name
+	membership.UpdatedAt = time.Now()
+	return membership, nil
+}
+
+// DeleteOrganization deletes the organization with its memberships and invitations; owners only.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID) (err error) {
+	defer func() { s.recordAudit(ctx, domain.AuditEventOrgDeleted, actorID, uuid.Nil, orgID, err, nil) }()
+
+	if _, err := s.requireRole(ctx, actorID, orgID, domain.OrgRoleOwner); err != nil {
+		return err
+	}
+
+	found, err := s.OrgRepo.DeleteOrganization(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	if !found {
+		return errOrganizationNotFound
+	}
+	return nil
+}
+
+// ListMembers returns the members; any member may see them.
+func (s *OrganizationService) ListMembers(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID) ([]domain.OrganizationMember, error) {
+	if _, err := s.requireRole(ctx, actorID, orgID, domain.OrgRoleMember); err != nil {
+		return nil, err
+	}
+
+	members, err := s.OrgRepo.ListMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during member lookup: %w", err)
+	}
+	return members, nil
+}
+
+// UpdateMemberRole changes a member's role. Admins manage admins and members; only owners can
+// make someone an owner or change an owner's role, and the last owner cannot be demoted.
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, userID uuid.UUID, req domain.UpdateMemberRoleRequest) (err error) {
+	var previousRole string
+	defer func() {
+		details := map[string]string{"role": req.Role, "previous_role": previousRole}
+		s.recordAudit(ctx, domain.AuditEventOrgMemberRoleChanged, actorID, userID, orgID, err, details)
+	}()
+
+	if !domain.IsOrgRole(req.Role) {
+		return errors.New("invalid organization role")
+	}
+
+	actor, err := s.requireRole(ctx, actorID, orgID, domain.OrgRoleAdmin)
+	if err != nil {
+		return err
+	}
+	previousRole, err = s.memberRole(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if (previousRole == domain.OrgRoleOwner || req.Role == domain.OrgRoleOwner) && actor.Role != domain.OrgRoleOwner {
+		return errOrgRoleInsufficient
+	}
+	if previousRole == req.Role {
+		return nil
+	}
+
+	found, err := s.OrgRepo.UpdateMemberRole(ctx, orgID, userID, req.Role)
+	if errors.Is(err, domain.ErrLastOrgOwner) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update member role: %w", err)
+	}
+	if !found {
+		return errors.New("member not found")
+	}
+
+	return nil
+}
+
+// RemoveMember removes a member. Members can always leave themselves; removing others needs the
+// admin role, and removing an owner the owner role. The last owner can neither leave nor be removed.
+func (s *OrganizationService) RemoveMember(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, userID uuid.UUID) (err error) {
+	defer func() { s.recordAudit(ctx, domain.AuditEventOrgMemberRemoved, actorID, userID, orgID, err, nil) }()
+
+	minRole := domain.OrgRoleAdmin
+	if actorID == userID {
+		minRole = domain.OrgRoleMember
+	}
+	actor, err := s.requireRole(ctx, actorID, orgID, minRole)
+	if err != nil {
+		return err
+	}
+	role, err := s.memberRole(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if role == domain.OrgRoleOwner && actor.Role != domain.OrgRoleOwner {
+		return errOrgRoleInsufficient
+	}
+
+	found, err := s.OrgRepo.RemoveMember(ctx, orgID, userID)
+	if errors.Is(err, domain.ErrLastOrgOwner) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	if !found {
+		return errors.New("member not found")
+	}
+
+	return nil
+}
+
+// InviteMember emails an invitation token; admins and owners only. Inviting an address again
+// replaces the pending invitation, so only the newest email works.
+func (s *OrganizationService) InviteMember(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, req domain.InviteMemberRequest) (resp *domain.OrganizationInvitation, token string, err error) {
+	emailAddress := strings.ToLower(strings.TrimSpace(req.Email))
+	defer func() {
+		details := map[string]string{"role": req.Role}
+		if resp != nil {
+			details["invitation_id"] = resp.ID.String()
+		}
+		event := auditResult(domain.AuditEventOrgMemberInvited, actorID, uuid.Nil, err, details)
+		event.Details["org_id"] = orgID.String()
+		event.Email = emailAddress
+		recordAudit(ctx, s.Audit, event)
+	}()
+
+	// 1. Check the caller's role and that the invitee is not a member yet
+	membership, err := s.requireRole(ctx, actorID, orgID, domain.OrgRoleAdmin)
+	if err != nil {
+		return nil, "", err
+	}
+	if req.Role != domain.OrgRoleAdmin && req.Role != domain.OrgRoleMember {
+		return nil, "", errors.New("invalid organization role")
+	}
+
+	invitee, err := s.AuthRepo.GetUserByEmail(ctx, emailAddress)
+	if err != nil {
+		return nil, "", fmt.Errorf("repository error during user lookup: %w", err)
+	}
+	if invitee != nil {
+		role, err := s.OrgRepo.GetMemberRole(ctx, orgID, invitee.ID)
+		if err != nil {
+			return nil, "", fmt.Errorf("repository error during member lookup: %w", err)
+		}
+		if role != "" {
+			return nil, "", errors.New("user is already a member")
+		}
+	}
+
+	// 2. Save only the hash of the token
+	token, err = security.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	now := time.Now()
+	invitation := domain.OrganizationInvitation{
+		ID:        uuid.New(),
+		OrgID:     orgID,
+		Email:     emailAddress,
+		Role:      req.Role,
+		TokenHash: security.HashToken(token),
+		InvitedBy: actorID,
+		ExpiresAt: now.Add(s.Config.InvitationTTL),
+		CreatedAt: now,
+	}
+	if err := s.OrgRepo.SaveInvitation(ctx, invitation); err != nil {
+		return nil, "", fmt.Errorf("failed to save invitation: %w", err)
+	}
+
+	// 3. Email the token (or a link if a frontend URL is configured)
+	inviterName := "A member"
+	if inviter, err := s.AuthRepo.GetUserByID(ctx, actorID); err == nil && inviter != nil {
+		inviterName = inviter.Name
+	}
+	link := ""
+	if s.Config.InvitationURL != "" {
+		if link, err = tokenLink(s.Config.InvitationURL, token); err != nil {
+			return nil, "", fmt.Errorf("failed to build invitation link: %w", err)
+		}
+	}
+	if err := s.EmailSender.SendOrganizationInvitation(emailAddress, membership.Name, inviterName, token, link); err != nil {
+		return nil, "", fmt.Errorf("failed to send invitation email: %w", err)
+	}
+
+	return &invitation, token, nil
+}
+
+// ListInvitations returns the pending invitations; admins and owners only.
+func (s *OrganizationService) ListInvitations(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID) ([]domain.OrganizationInvitation, error) {
+	if _, err := s.requireRole(ctx, actorID, orgID, domain.OrgRoleAdmin); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.OrgRepo.ListInvitations(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during invitation lookup: %w", err)
+	}
+	return invitations, nil
+}
+
+// RevokeInvitation deletes a pending invitation, so its token stops working; admins and owners only.
+func (s *OrganizationService) RevokeInvitation(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, invitationID uuid.UUID) (err error) {
+	defer func() {
+		details := map[string]string{"invitation_id": invitationID.String()}
+		s.recordAudit(ctx, domain.AuditEventOrgInvitationRevoked, actorID, uuid.Nil, orgID, err, details)
+	}()
+
+	if _, err := s.requireRole(ctx, actorID, orgID, domain.OrgRoleAdmin); err != nil {
+		return err
+	}
+
+	found, err := s.OrgRepo.DeleteInvitation(ctx, orgID, invitationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete invitation: %w", err)
+	}
+	if !found {
+		return errors.New("invitation not found")
+	}
+	return nil
+}
+
+// AcceptInvitation adds the caller with the invited role. The invitation must have been sent to
+// the caller's email address, and that address must be verified, so a forwarded email is useless.
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, actorID uuid.UUID, req domain.InvitationTokenRequest) (resp *domain.OrganizationMembership, err error) {
+	var orgID uuid.UUID
+	defer func() {
+		var details map[string]string
+		if resp != nil {
+			details = map[string]string{"role": resp.Role}
+		}
+		s.recordAudit(ctx, domain.AuditEventOrgMemberJoined, actorID, actorID, orgID, err, details)
+	}()
+
+	// 1. Look up the invitation
+	invitation, err := s.OrgRepo.GetInvitationByTokenHash(ctx, security.HashToken(req.Token))
+	if err != nil {
+		return nil, fmt.Errorf("repository error during invitation lookup: %w", err)
+	}
+	if invitation == nil || !invitation.ExpiresAt.After(time.Now()) {
+		return nil, errInvalidInvitation
+	}
+	orgID = invitation.OrgID
+
+	// 2. Check that it is addressed to the caller
+	user, err := s.AuthRepo.GetUserByID(ctx, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during user lookup: %w", err)
+	}
+	if user == nil || !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, errors.New("invitation was sent to a different email address")
+	}
+	if !user.IsVerified {
+		return nil, errors.New("email address must be verified to accept an invitation")
+	}
+
+	// 3. Consume the invitation and add the membership in one step
+	accepted, err := s.OrgRepo.AcceptInvitation(ctx, *invitation, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	if !accepted {
+		return nil, errInvalidInvitation
+	}
+
+	return s.requireRole(ctx, actorID, orgID, domain.OrgRoleMember)
+}
+
+// DeclineInvitation deletes the invitation. Expired invitations can be declined too.
+func (s *OrganizationService) DeclineInvitation(ctx context.Context, req domain.InvitationTokenRequest) (err error) {
+	var invitation *domain.OrganizationInvitation
+	defer func() {
+		event := auditResult(domain.AuditEventOrgInvitationDeclined, uuid.Nil, uuid.Nil, err, nil)
+		if invitation != nil {
+			event.Details = map[string]string{"org_id": invitation.OrgID.String(), "invitation_id": invitation.ID.String()}
+			event.Email = invitation.Email
+		}
+		recordAudit(ctx, s.Audit, event)
+	}()
+
+	invitation, err = s.OrgRepo.GetInvitationByTokenHash(ctx, security.HashToken(req.Token))
+	if err != nil {
+		return fmt.Errorf("repository error during invitation lookup: %w", err)
+	}
+	if invitation == nil {
+		return errInvalidInvitation
+	}
+
+	found, err := s.OrgRepo.DeleteInvitation(ctx, invitation.OrgID, invitation.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete invitation: %w", err)
+	}
+	if !found {
+		return errInvalidInvitation
+	}
+	return nil
+}
+
+// requireRole loads the organization and checks that the caller holds at least minRole in it.
+func (s *OrganizationService) requireRole(ctx context.Context, actorID uuid.UUID, orgID uuid.UUID, minRole string) (*domain.OrganizationMembership, error) {
+	org, err := s.OrgRepo.GetOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during organization lookup: %w", err)
+	}
+	if org == nil {
+		return nil, errOrganizationNotFound
+	}
+
+	role, err := s.OrgRepo.GetMemberRole(ctx, orgID, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during member lookup: %w", err)
+	}
+	if role == "" {
+		return nil, errOrganizationNotFound
+	}
+	if !domain.OrgRoleAtLeast(role, minRole) {
+		return nil, errOrgRoleInsufficient
+	}
+
+	return &domain.OrganizationMembership{Organization: *org, Role: role}, nil
+}
+
+// memberRole returns the role of the member being managed.
+func (s *OrganizationService) memberRole(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (string, error) {
+	role, err := s.OrgRepo.GetMemberRole(ctx, orgID, userID)
+	if err != nil {
+		return "", fmt.Errorf("repository error during member lookup: %w", err)
+	}
+	if role == "" {
+		return "", errors.New("member not found")
+	}
+	return role, nil
+}
+
+// recordAudit records an organization event; the organization ID is kept in the details.
+func (s *OrganizationService) recordAudit(ctx context.Context, eventType string, actorID uuid.UUID, subjectID uuid.UUID, orgID uuid.UUID, err error, details map[string]string) {
+	event := auditResult(eventType, actorID, subjectID, err, details)
+	if orgID != uuid.Nil {
+		if event.Details == nil {
+			event.Details = map[string]string{}
+		}
+		event.Details["org_id"] = orgID.String()
+	}
+	recordAudit(ctx, s.Audit, event)
+}
+
+// organizationName trims the name and rejects names that are only whitespace.
+func organizationName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("organization name must not be empty")
+	}
+	return name, nil
+}
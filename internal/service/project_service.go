@@ -0,0 +1,416 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// projectKeyPattern matches valid project keys such as "MANPRO" (the database checks the same).
+var projectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+var (
+	// errProjectNotFound is also returned to non-members, so they cannot probe which projects exist.
+	errProjectNotFound         = errors.New("project not found")
+	errProjectRoleInsufficient = errors.New("insufficient project role")
+	errProjectArchived         = errors.New("project is archived")
+)
+
+// ProjectService is the concrete implementation of the ports.ProjectService interface.
+type ProjectService struct {
+	ProjectRepo ports.ProjectRepository
+	AuthRepo    ports.AuthRepository
+	Audit       ports.AuditLogger
+}
+
+// NewProjectService creates a new instance of the ProjectService.
+func NewProjectService(projectRepo ports.ProjectRepository, authRepo ports.AuthRepository, audit ports.AuditLogger) ports.ProjectService {
+	return &ProjectService{
+		ProjectRepo: projectRepo,
+		AuthRepo:    authRepo,
+		Audit:       audit,
+	}
+}
+
+// CreateProject validates the key and dates and creates the project with the caller as owner and admin.
+// Keys are case-insensitive on input and stored in upper case.
+func (s *ProjectService) CreateProject(ctx context.Context, actorID uuid.UUID, req domain.CreateProjectRequest) (resp *domain.ProjectMembership, err error) {
+	key := strings.ToUpper(strings.TrimSpace(req.Key))
+	defer func() {
+		var projectID uuid.UUID
+		if resp != nil {
+			projectID = resp.ID
+		}
+		s.recordAudit(ctx, domain.AuditEventProjectCreated, actorID, uuid.Nil, projectID, key, err, nil)
+	}()
+
+	// 1. Validate the input
+	if !projectKeyPattern.MatchString(key) {
+		return nil, errors.New("invalid project key")
+	}
+	now := time.Now()
+	project := domain.Project{
+		ID:          uuid.New(),
+		Key:         key,
+		Description: strings.TrimSpace(req.Description),
+		OwnerID:     actorID,
+		Status:      req.Status,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if project.Status == "" {
+		project.Status = domain.ProjectStatusPlanned
+	}
+	if err := applyProjectChanges(&project, &req.Name, &req.StartDate, &req.EndDate); err != nil {
+		return nil, err
+	}
+
+	// 2. Save the project; the repository reports a taken key
+	if err := s.ProjectRepo.CreateProject(ctx, project); err != nil {
+		if err.Error() == "project key already in use" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	return &domain.ProjectMembership{Project: project, Role: domain.ProjectRoleAdmin}, nil
+}
+
+// ListProjects applies the paging defaults and returns one page of the caller's projects.
+func (s *ProjectService) ListProjects(ctx context.Context, actorID uuid.UUID, req domain.ListProjectsRequest) (*domain.ProjectListResponse, error) {
+	page, pageSize := pagination(req.Page, req.PageSize)
+
+	projects, total, err := s.ProjectRepo.ListProjects(ctx, domain.ProjectFilter{
+		UserID:          actorID,
+		Status:          req.Status,
+		IncludeArchived: req.IncludeArchived,
+		Limit:           pageSize,
+		Offset:          (page - 1) * pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository error during project listing: %w", err)
+	}
+
+	return &domain.ProjectListResponse{
+		Projects: projects,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetProject returns the project; any member may see it.
+func (s *ProjectService) GetProject(ctx context.Context, actorID uuid.UUID, idOrKey string) (*domain.ProjectMembership, error) {
+	return s.requireRole(ctx, actorID, idOrKey, domain.ProjectRoleViewer)
+}
+
+// UpdateProject changes the fields present in the request; editors and admins only.
+func (s *ProjectService) UpdateProject(ctx context.Context, actorID uuid.UUID, idOrKey string, req domain.UpdateProjectRequest) (*domain.ProjectMembership, error) {
+	membership, err := s.requireWritable(ctx, actorID, idOrKey, domain.ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	project := membership.Project
+	if req.Description != nil {
+		project.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Status != nil {
+		project.Status = *req.Status
+	}
+	if err := applyProjectChanges(&project, req.Name, req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	found, err := s.ProjectRepo.UpdateProject(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", err)
+	}
+	if !found {
+		return nil, errProjectNotFound
+	}
+
+	project.UpdatedAt = time.Now()
+	return &domain.ProjectMembership{Project: project, Role: membership.Role}, nil
+}
+
+// SetProjectArchived archives or unarchives the project; admins only. Archived projects are
+// read-only and left out of the project list unless asked for.
+func (s *ProjectService) SetProjectArchived(ctx context.Context, actorID uuid.UUID, idOrKey string, archived bool) (resp *domain.ProjectMembership, err error) {
+	var membership *domain.ProjectMembership
+	defer func() {
+		eventType := domain.AuditEventProjectUnarchived
+		if archived {
+			eventType = domain.AuditEventProjectArchived
+		}
+		s.recordProjectAudit(ctx, eventType, actorID, uuid.Nil, membership, err, nil)
+	}()
+
+	membership, err = s.requireRole(ctx, actorID, idOrKey, domain.ProjectRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.ProjectRepo.SetProjectArchived(ctx, membership.ID, archived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", err)
+	}
+	if !found {
+		return nil, errProjectNotFound
+	}
+
+	return s.requireRole(ctx, actorID, membership.ID.String(), domain.ProjectRoleAdmin)
+}
+
+// DeleteProject permanently deletes the project with its memberships; admins only.
+// Unlike other changes, an archived project can be deleted.
+func (s *ProjectService) DeleteProject(ctx context.Context, actorID uuid.UUID, idOrKey string) (err error) {
+	var membership *domain.ProjectMembership
+	defer func() {
+		s.recordProjectAudit(ctx, domain.AuditEventProjectDeleted, actorID, uuid.Nil, membership, err, nil)
+	}()
+
+	membership, err = s.requireRole(ctx, actorID, idOrKey, domain.ProjectRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	found, err := s.ProjectRepo.DeleteProject(ctx, membership.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	if !found {
+		return errProjectNotFound
+	}
+	return nil
+}
+
+// ListMembers returns the members; any member may see them.
+func (s *ProjectService) ListMembers(ctx context.Context, actorID uuid.UUID, idOrKey string) ([]domain.ProjectMember, error) {
+	membership, err := s.requireRole(ctx, actorID, idOrKey, domain.ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.ProjectRepo.ListMembers(ctx, membership.ID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during member lookup: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember adds an existing user, found by email; admins only.
+func (s *ProjectService) AddMember(ctx context.Context, actorID uuid.UUID, idOrKey string, req domain.AddProjectMemberRequest) (resp *domain.ProjectMember, err error) {
+	var membership *domain.ProjectMembership
+	defer func() {
+		var userID uuid.UUID
+		if resp != nil {
+			userID = resp.UserID
+		}
+		s.recordProjectAudit(ctx, domain.AuditEventProjectMemberAdded, actorID, userID, membership, err, map[string]string{"role": req.Role})
+	}()
+
+	membership, err = s.requireWritable(ctx, actorID, idOrKey, domain.ProjectRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.AuthRepo.GetUserByEmail(ctx, strings.TrimSpace(req.Email))
+	if err != nil {
+		return nil, fmt.Errorf("repository error during user lookup: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	added, err := s.ProjectRepo.AddMember(ctx, membership.ID, user.ID, req.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add member: %w", err)
+	}
+	if !added {
+		return nil, errors.New("user is already a project member")
+	}
+
+	return &domain.ProjectMember{
+		ProjectID: membership.ID,
+		UserID:    user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      req.Role,
+		AddedAt:   time.Now(),
+	}, nil
+}
+
+// UpdateMemberRole changes a member's role; admins only. The owner always stays an admin,
+// and the last admin cannot be demoted, so every project keeps someone who can manage it.
+func (s *ProjectService) UpdateMemberRole(ctx context.Context, actorID uuid.UUID, idOrKey string, userID uuid.UUID, req domain.UpdateProjectMemberRequest) (err error) {
+	var membership *domain.ProjectMembership
+	defer func() {
+		s.recordProjectAudit(ctx, domain.AuditEventProjectMemberRoleChanged, actorID, userID, membership, err, map[string]string{"role": req.Role})
+	}()
+
+	membership, err = s.requireWritable(ctx, actorID, idOrKey, domain.ProjectRoleAdmin)
+	if err != nil {
+		return err
+	}
+	if userID == membership.OwnerID && req.Role != domain.ProjectRoleAdmin {
+		return errors.New("cannot change the role of the project owner")
+	}
+
+	found, err := s.ProjectRepo.UpdateMemberRole(ctx, membership.ID, userID, req.Role)
+	if errors.Is(err, domain.ErrLastProjectAdmin) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update member role: %w", err)
+	}
+	if !found {
+		return errors.New("member not found")
+	}
+	return nil
+}
+
+// RemoveMember removes a member; admins only, but members can leave themselves, even from an archived
+// project. The owner and the last admin cannot be removed.
+func (s *ProjectService) RemoveMember(ctx context.Context, actorID uuid.UUID, idOrKey string, userID uuid.UUID) (err error) {
+	var membership *domain.ProjectMembership
+	defer func() {
+		s.recordProjectAudit(ctx, domain.AuditEventProjectMemberRemoved, actorID, userID, membership, err, nil)
+	}()
+
+	if actorID == userID {
+		membership, err = s.requireRole(ctx, actorID, idOrKey, domain.ProjectRoleViewer)
+	} else {
+		membership, err = s.requireWritable(ctx, actorID, idOrKey, domain.ProjectRoleAdmin)
+	}
+	if err != nil {
+		return err
+	}
+	if userID == membership.OwnerID {
+		return errors.New("cannot remove the project owner")
+	}
+
+	found, err := s.ProjectRepo.RemoveMember(ctx, membership.ID, userID)
+	if errors.Is(err, domain.ErrLastProjectAdmin) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	if !found {
+		return errors.New("member not found")
+	}
+	return nil
+}
+
+// requireRole loads the project by ID or key and checks that the caller holds at least minRole in it.
+func (s *ProjectService) requireRole(ctx context.Context, actorID uuid.UUID, idOrKey string, minRole string) (*domain.ProjectMembership, error) {
+	var project *domain.Project
+	var err error
+	if projectID, parseErr := uuid.Parse(idOrKey); parseErr == nil {
+		project, err = s.ProjectRepo.GetProject(ctx, projectID)
+	} else {
+		project, err = s.ProjectRepo.GetProjectByKey(ctx, strings.ToUpper(idOrKey))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository error during project lookup: %w", err)
+	}
+	if project == nil {
+		return nil, errProjectNotFound
+	}
+
+	role, err := s.ProjectRepo.GetMemberRole(ctx, project.ID, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during member lookup: %w", err)
+	}
+	if role == "" {
+		return nil, errProjectNotFound
+	}
+	if !domain.ProjectRoleAtLeast(role, minRole) {
+		return nil, errProjectRoleInsufficient
+	}
+
+	return &domain.ProjectMembership{Project: *project, Role: role}, nil
+}
+
+// requireWritable is requireRole for changes, which are refused while the project is archived.
+func (s *ProjectService) requireWritable(ctx context.Context, actorID uuid.UUID, idOrKey string, minRole string) (*domain.ProjectMembership, error) {
+	membership, err := s.requireRole(ctx, actorID, idOrKey, minRole)
+	if err != nil {
+		return nil, err
+	}
+	if membership.IsArchived() {
+		return nil, errProjectArchived
+	}
+	return membership, nil
+}
+
+// applyProjectChanges sets the name and dates that are not nil and validates the result.
+// An empty date clears it.
+func applyProjectChanges(project *domain.Project, name *string, startDate *string, endDate *string) error {
+	if name != nil {
+		project.Name = strings.TrimSpace(*name)
+		if project.Name == "" {
+			return errors.New("project name must not be empty")
+		}
+	}
+
+	var err error
+	if startDate != nil {
+		if project.StartDate, err = parseProjectDate(*startDate); err != nil {
+			return err
+		}
+	}
+	if endDate != nil {
+		if project.EndDate, err = parseProjectDate(*endDate); err != nil {
+			return err
+		}
+	}
+	if project.StartDate != nil && project.EndDate != nil && project.EndDate.Before(*project.StartDate) {
+		return errors.New("end date must not be before start date")
+	}
+	return nil
+}
+
+// parseProjectDate parses a date in domain.ProjectDateLayout; an empty value means no date.
+func parseProjectDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(domain.ProjectDateLayout, value)
+	if err != nil {
+		return nil, errors.New("dates must use the format YYYY-MM-DD")
+	}
+	return &date, nil
+}
+
+// recordProjectAudit records a project event for a loaded project (nil if it could not be loaded).
+func (s *ProjectService) recordProjectAudit(ctx context.Context, eventType string, actorID uuid.UUID, subjectID uuid.UUID, membership *domain.ProjectMembership, err error, details map[string]string) {
+	if membership == nil {
+		s.recordAudit(ctx, eventType, actorID, subjectID, uuid.Nil, "", err, details)
+		return
+	}
+	s.recordAudit(ctx, eventType, actorID, subjectID, membership.ID, membership.Key, err, details)
+}
+
+// recordAudit records a project event; the project ID and key are kept in the details.
+func (s *ProjectService) recordAudit(ctx context.Context, eventType string, actorID uuid.UUID, subjectID uuid.UUID, projectID uuid.UUID, key string, err error, details map[string]string) {
+	event := auditResult(eventType, actorID, subjectID, err, details)
+	if event.Details == nil {
+		event.Details = map[string]string{}
+	}
+	if projectID != uuid.Nil {
+		event.Details["project_id"] = projectID.String()
+	}
+	if key != "" {
+		event.Details["project_key"] = key
+	}
+	recordAudit(ctx, s.Audit, event)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// RunPruner periodically deletes revocation entries whose tokens have already expired, and
+// failed-attempt counters that neither count nor lock out anything any more (see LockoutConfig.Window).
+// It blocks until ctx is cancelled, so it should be started in its own goroutine.
+func RunPruner(ctx context.Context, revocations ports.RevocationStore, attempts ports.AttemptStore, attemptWindow time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := revocations.PruneExpired(ctx)
+			if err != nil {
+				log.Printf("ERROR: Failed to prune expired token revocations: %v", err)
+			} else if removed > 0 {
+				log.Printf("Pruned %d expired token revocation entries.", removed)
+			}
+
+			removed, err = attempts.PruneExpired(ctx, attemptWindow)
+			if err != nil {
+				log.Printf("ERROR: Failed to prune expired failed-attempt counters: %v", err)
+			} else if removed > 0 {
+				log.Printf("Pruned %d expired failed-attempt counters.", removed)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+)
+
+// SessionService is the concrete implementation of the ports.SessionService interface.
+type SessionService struct {
+	SessionRepo ports.SessionRepository
+}
+
+// NewSessionService creates a new instance of the SessionService.
+func NewSessionService(sessionRepo ports.SessionRepository) ports.SessionService {
+	return &SessionService{
+		SessionRepo: sessionRepo,
+	}
+}
+
+// ListSessions returns the user's active sessions, marking the one the request was made with.
+func (s *SessionService) ListSessions(ctx context.Context, userID uuid.UUID, currentSessionID uuid.UUID) ([]domain.SessionResponse, error) {
+	sessions, err := s.SessionRepo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error during session lookup: %w", err)
+	}
+
+	response := make([]domain.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, domain.SessionResponse{
+			Session: session,
+			Current: session.ID == currentSessionID,
+		})
+	}
+	return response, nil
+}
+
+// RevokeSession revokes one of the user's sessions. Access tokens of that session are rejected
+// from the next request on, and its refresh tokens can no longer be used.
+func (s *SessionService) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) error {
+	revoked, err := s.SessionRepo.RevokeSession(ctx, userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !revoked {
+		return errors.New("session not found")
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	"github.com/mitcheltastic/ManproBackend/internal/core/ports"
+	"github.com/mitcheltastic/ManproBackend/internal/pkg/security"
+)
+
+// SessionTouchInterval limits how often a request updates the last_seen_at of its session.
+const SessionTouchInterval = time.Minute
+
+// LocalTokenVerifier implements ports.TokenVerifier for the JWTs issued by our own /auth endpoints.
+// With RequireVerified set (EMAIL_VERIFICATION_POLICY=block_protected), tokens of unverified
+// accounts are refused with domain.ErrEmailNotVerified.
+type LocalTokenVerifier struct {
+	JWTService      security.JWTService
+	Revocations     ports.RevocationStore
+	SessionRepo     ports.SessionRepository
+	RequireVerified bool
+}
+
+// NewLocalTokenVerifier creates a new instance of the LocalTokenVerifier.
+func NewLocalTokenVerifier(jwtService security.JWTService, revocations ports.RevocationStore, sessionRepo ports.SessionRepository, requireVerified bool) ports.TokenVerifier {
+	return &LocalTokenVerifier{
+		JWTService:      jwtService,
+		Revocations:     revocations,
+		SessionRepo:     sessionRepo,
+		RequireVerified: requireVerified,
+	}
+}
+
+// VerifyToken checks the signature, the revocation list and the verification policy,
+// and records the activity on the session.
+func (v *LocalTokenVerifier) VerifyToken(ctx context.Context, token string) (*domain.Principal, error) {
+	claims, err := v.JWTService.ValidateToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidToken, err)
+	}
+
+	revoked, err := v.Revocations.IsRevoked(ctx, claims.ID, claims.UserID, claims.SessionID, claims.IssuedAt.Time)
+	if err != nil {
+		return nil, fmt.Errorf("token revocation check failed: %w", err)
+	}
+	if revoked {
+		return nil, fmt.Errorf("%w: token has been revoked", domain.ErrInvalidToken)
+	}
+	if v.RequireVerified && !claims.EmailVerified {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	if claims.SessionID != uuid.Nil {
+		if err := v.SessionRepo.TouchSession(ctx, claims.SessionID, SessionTouchInterval); err != nil {
+			// Not fatal: the last-seen time is informational only
+			log.Printf("Session touch error: %v", err)
+		}
+	}
+
+	return LocalPrincipal(claims), nil
+}
+
+// LocalPrincipal converts the claims of a local JWT into a Principal.
+func LocalPrincipal(claims *security.UserClaims) *domain.Principal {
+	principal := &domain.Principal{
+		UserID:        claims.UserID.String(),
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Roles:         claims.Roles,
+		Provider:      domain.PrincipalProviderLocal,
+		SessionID:     claims.SessionID,
+		TokenID:       claims.ID,
+	}
+	if claims.ExpiresAt != nil {
+		principal.ExpiresAt = &claims.ExpiresAt.Time
+	}
+	return principal
+}
+
+// FirebaseTokenVerifier implements ports.TokenVerifier for Firebase ID tokens. If the Firebase account
+// is linked to a local user, the Principal carries the local user ID and roles, so one person has a
+// single identity whichever way they sign in, and a disabled user is refused even while their Firebase
+// token is still valid. Unlinked users keep the Firebase UID and the roles custom claim, or
+// domain.DefaultRole if the token has none (like a newly registered local account).
+type FirebaseTokenVerifier struct {
+	Firebase ports.TokenVerifier
+	AuthRepo ports.AuthRepository
+	RoleRepo ports.RoleRepository
+}
+
+// NewFirebaseTokenVerifier creates a new instance of the FirebaseTokenVerifier.
+func NewFirebaseTokenVerifier(firebase ports.TokenVerifier, authRepo ports.AuthRepository, roleRepo ports.RoleRepository) ports.TokenVerifier {
+	return &FirebaseTokenVerifier{
+		Firebase: firebase,
+		AuthRepo: authRepo,
+		RoleRepo: roleRepo,
+	}
+}
+
+// VerifyToken verifies the token with Firebase and maps it to the linked local account, if any.
+// It returns domain.ErrAccountDisabled if that account is disabled.
+func (v *FirebaseTokenVerifier) VerifyToken(ctx context.Context, token string) (*domain.Principal, error) {
+	principal, err := v.Firebase.VerifyToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := v.AuthRepo.GetUserByFirebaseUID(ctx, principal.FirebaseUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the account of Firebase user %s: %w", principal.FirebaseUID, err)
+	}
+	if user == nil {
+		if len(principal.Roles) == 0 {
+			principal.Roles = []string{domain.DefaultRole}
+		}
+		return principal, nil
+	}
+	if user.IsDisabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	roles, err := v.RoleRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load roles of user %s: %w", user.ID, err)
+	}
+	principal.UserID = user.ID.String()
+	principal.Roles = roles
+	return principal, nil
+}
+
+// AccessTokenVerifier implements ports.TokenVerifier for personal access tokens. Like
+// LocalTokenVerifier, it refuses unverified accounts if RequireVerified is set.
+type AccessTokenVerifier struct {
+	Tokens          ports.PersonalAccessTokenService
+	RequireVerified bool
+}
+
+// NewAccessTokenVerifier creates a new instance of the AccessTokenVerifier.
+func NewAccessTokenVerifier(tokens ports.PersonalAccessTokenService, requireVerified bool) ports.TokenVerifier {
+	return &AccessTokenVerifier{
+		Tokens:          tokens,
+		RequireVerified: requireVerified,
+	}
+}
+
+// VerifyToken authenticates a personal access token (mp_pat_...) and records its use.
+func (v *AccessTokenVerifier) VerifyToken(ctx context.Context, token string) (*domain.Principal, error) {
+	if !strings.HasPrefix(token, domain.PersonalAccessTokenPrefix) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	auth, err := v.Tokens.Authenticate(ctx, token)
+	if err != nil {
+		if errors.Is(err, errInvalidAccessToken) {
+			return nil, fmt.Errorf("%w: %w", domain.ErrInvalidToken, err)
+		}
+		// Including domain.ErrAccountDisabled, which ends the chain with a 403
+		return nil, err
+	}
+	if v.RequireVerified && !auth.User.IsVerified {
+		return nil, domain.ErrEmailNotVerified
+	}
+	return AccessTokenPrincipal(auth), nil
+}
+
+// AccessTokenPrincipal converts an authenticated personal access token into a Principal limited to its scopes.
+func AccessTokenPrincipal(auth *domain.PersonalAccessTokenAuth) *domain.Principal {
+	return &domain.Principal{
+		UserID:        auth.User.ID.String(),
+		Email:         auth.User.Email,
+		EmailVerified: auth.User.IsVerified,
+		Name:          auth.User.Name,
+		Roles:         auth.Roles,
+		Provider:      domain.PrincipalProviderAccessToken,
+		Scopes:        auth.Token.Scopes,
+		ExpiresAt:     auth.Token.ExpiresAt,
+	}
+}
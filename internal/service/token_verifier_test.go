@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitcheltastic/ManproBackend/internal/core/domain"
+	fbclient "github.com/mitcheltastic/ManproBackend/internal/infrastructure/firebase"
+)
+
+func (r *claimsUsers) GetUserByFirebaseUID(ctx context.Context, firebaseUID string) (*domain.User, error) {
+	for i := range r.users {
+		if r.users[i].FirebaseUID == firebaseUID {
+			user := r.users[i]
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestFirebaseTokenVerifierMapsLinkedAccounts(t *testing.T) {
+	secret := []byte("verifier-test-secret")
+	fake, err := fbclient.NewFakeVerifier(secret, "")
+	if err != nil {
+		t.Fatalf("NewFakeVerifier: %v", err)
+	}
+	disabledAt := time.Now()
+	linked := domain.User{ID: uuid.New(), FirebaseUID: "fb-linked"}
+	disabled := domain.User{ID: uuid.New(), FirebaseUID: "fb-disabled", DisabledAt: &disabledAt}
+	verifier := NewFirebaseTokenVerifier(
+		fake,
+		&claimsUsers{users: []domain.User{linked, disabled}},
+		&claimsRoles{roles: map[uuid.UUID][]string{linked.ID: {"member", "manager"}}},
+	)
+
+	verify := func(uid string) (*domain.Principal, error) {
+		claims := fbclient.FakeTokenClaims{Email: uid + "@example.com", Roles: []string{"viewer"}}
+		token, err := fbclient.SignFakeToken(secret, "", uid, claims, time.Hour)
+		if err != nil {
+			t.Fatalf("SignFakeToken: %v", err)
+		}
+		return verifier.VerifyToken(context.Background(), token)
+	}
+
+	principal, err := verify("fb-linked")
+	if err != nil {
+		t.Fatalf("linked user: %v", err)
+	}
+	if id, ok := principal.LocalUserID(); !ok || id != linked.ID {
+		t.Errorf("linked user: LocalUserID = %v, %v; want %v", id, ok, linked.ID)
+	}
+	if principal.FirebaseUID != "fb-linked" || !slices.Equal(principal.Roles, []string{"member", "manager"}) {
+		t.Errorf("linked user: principal = %+v, want the local roles", *principal)
+	}
+
+	principal, err = verify("fb-unlinked")
+	if err != nil {
+		t.Fatalf("unlinked user: %v", err)
+	}
+	if _, ok := principal.LocalUserID(); ok || principal.UserID != "fb-unlinked" {
+		t.Errorf("unlinked user: principal = %+v, want the Firebase UID only", *principal)
+	}
+	if !slices.Equal(principal.Roles, []string{"viewer"}) {
+		t.Errorf("unlinked user: roles = %v, want the roles claim", principal.Roles)
+	}
+
+	token, err := fbclient.SignFakeToken(secret, "", "fb-unsynced", fbclient.FakeTokenClaims{}, time.Hour)
+	if err != nil {
+		t.Fatalf("SignFakeToken: %v", err)
+	}
+	principal, err = verifier.VerifyToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("user without a roles claim: %v", err)
+	}
+	if !slices.Equal(principal.Roles, []string{domain.DefaultRole}) {
+		t.Errorf("user without a roles claim: roles = %v, want the default role", principal.Roles)
+	}
+
+	if _, err := verify("fb-disabled"); !errors.Is(err, domain.ErrAccountDisabled) {
+		t.Errorf("disabled user: err = %v, want ErrAccountDisabled", err)
+	}
+}
@@ -0,0 +1,29 @@
+-- +goose Up
+-- Stores opaque refresh tokens used to renew short-lived access tokens.
+
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    -- All tokens rotated from the same login share a family, so reuse can revoke them together
+    family_id UUID NOT NULL,
+
+    -- SHA-256 of the token; the raw token is never stored
+    token_hash TEXT UNIQUE NOT NULL,
+
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+
+    -- Set when the token is exchanged for a new one (rotation)
+    used_at TIMESTAMP WITH TIME ZONE,
+    replaced_by UUID,
+
+    -- Set when the family is revoked (reuse detection, logout)
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+
+-- +goose Down
+DROP TABLE refresh_tokens;
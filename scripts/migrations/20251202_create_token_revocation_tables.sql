@@ -0,0 +1,27 @@
+-- +goose Up
+-- Server-side revocation of access tokens (logout and logout-all).
+
+-- Table 1: revoked_tokens (a single access token revoked by its 'jti' claim)
+CREATE TABLE revoked_tokens (
+    jti TEXT PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    -- When the revoked token would have expired; the row can be pruned after this
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- Table 2: user_token_revocations (every token of a user issued before a point in time)
+CREATE TABLE user_token_revocations (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_before TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_user_token_revocations_expires_at ON user_token_revocations(expires_at);
+
+-- +goose Down
+DROP TABLE user_token_revocations;
+DROP TABLE revoked_tokens;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Stores the tokens emailed to users to confirm their address (sets users.is_verified).
+
+CREATE TABLE email_verification_tokens (
+    -- SHA-256 of the token; the raw token is only sent by email
+    token_hash TEXT PRIMARY KEY,
+
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens(user_id);
+
+-- +goose Down
+DROP TABLE email_verification_tokens;
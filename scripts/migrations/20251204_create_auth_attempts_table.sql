@@ -0,0 +1,23 @@
+-- +goose Up
+-- Failed-attempt counters for brute-force protection, shared by every server instance.
+
+CREATE TABLE auth_attempts (
+    -- e.g. 'login:email:<email>', 'login:ip:<ip>', 'reset:email:<email>'
+    key TEXT PRIMARY KEY,
+
+    -- Consecutive failures inside the counting window
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    -- While set and in the future, attempts for this key are rejected with 429
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_auth_attempts_last_failure_at ON auth_attempts(last_failure_at);
+
+-- Wrong guesses against the current reset code; the code is invalidated after too many
+ALTER TABLE password_reset_tokens ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE password_reset_tokens DROP COLUMN attempts;
+DROP TABLE auth_attempts;
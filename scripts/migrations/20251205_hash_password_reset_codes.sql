@@ -0,0 +1,14 @@
+-- +goose Up
+-- Stores only a keyed hash (HMAC-SHA256) of password reset codes instead of the plaintext code.
+-- Pending codes cannot be converted, so they are discarded; users simply request a new one.
+
+DELETE FROM password_reset_tokens;
+
+ALTER TABLE password_reset_tokens DROP COLUMN code;
+ALTER TABLE password_reset_tokens ADD COLUMN code_hash TEXT NOT NULL;
+
+-- +goose Down
+DELETE FROM password_reset_tokens;
+
+ALTER TABLE password_reset_tokens DROP COLUMN code_hash;
+ALTER TABLE password_reset_tokens ADD COLUMN code CHAR(6) NOT NULL;
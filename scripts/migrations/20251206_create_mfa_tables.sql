@@ -0,0 +1,33 @@
+-- +goose Up
+-- TOTP two-factor authentication and single-use recovery codes.
+
+-- Table 1: user_totp (one authenticator per user)
+CREATE TABLE user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+
+    -- Shared secret encrypted with AES-GCM (see security.EncryptSecret)
+    secret_encrypted TEXT NOT NULL,
+
+    -- NULL while the enrollment is pending confirmation
+    enabled_at TIMESTAMP WITH TIME ZONE,
+
+    -- Last accepted time step, so the same code cannot be replayed
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+-- Table 2: mfa_recovery_codes (keyed hashes of single-use backup codes)
+CREATE TABLE mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+
+    UNIQUE (user_id, code_hash)
+);
+
+-- +goose Down
+DROP TABLE mfa_recovery_codes;
+DROP TABLE user_totp;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Links local users to Firebase accounts so both sign-in methods resolve to the same user ID.
+
+ALTER TABLE users ADD COLUMN firebase_uid TEXT UNIQUE;
+
+-- Users created through Firebase sign-in have no local password
+ALTER TABLE users ALTER COLUMN hashed_password DROP NOT NULL;
+
+-- +goose Down
+UPDATE users SET hashed_password = '' WHERE hashed_password IS NULL;
+ALTER TABLE users ALTER COLUMN hashed_password SET NOT NULL;
+
+ALTER TABLE users DROP COLUMN firebase_uid;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Pending email address changes, confirmed with a code sent to the new address.
+
+CREATE TABLE email_change_requests (
+    -- One pending change per user; requesting again replaces it
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+
+    new_email VARCHAR(255) NOT NULL,
+
+    -- Keyed hash of the 6-digit code (see security.HashCode)
+    code_hash TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+-- +goose Down
+DROP TABLE email_change_requests;
@@ -0,0 +1,66 @@
+-- +goose Up
+-- Role-based access control. Roles group permissions; users hold one or more roles.
+-- The first administrator is granted by hand:
+--   INSERT INTO user_roles (user_id, role) SELECT id, 'admin' FROM users WHERE email = '...';
+
+CREATE TABLE roles (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    -- System roles are seeded here and cannot be deleted through the API
+    is_system BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE permissions (
+    -- Permission names follow 'resource:action', e.g. 'project:write'
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission TEXT NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE user_roles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (user_id, role)
+);
+
+INSERT INTO roles (name, description, is_system) VALUES
+    ('admin', 'Full access, including user and role management', TRUE),
+    ('manager', 'Manages projects and can view users', TRUE),
+    ('member', 'Works on projects', TRUE),
+    ('guest', 'Read-only access to projects', TRUE);
+
+INSERT INTO permissions (name, description) VALUES
+    ('project:read', 'View projects'),
+    ('project:write', 'Create and edit projects'),
+    ('project:delete', 'Delete projects'),
+    ('user:read', 'View user accounts'),
+    ('user:write', 'Manage user accounts'),
+    ('role:assign', 'Grant and revoke roles');
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', name FROM permissions;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('manager', 'project:read'),
+    ('manager', 'project:write'),
+    ('manager', 'project:delete'),
+    ('manager', 'user:read'),
+    ('member', 'project:read'),
+    ('member', 'project:write'),
+    ('guest', 'project:read');
+
+-- Existing accounts become members, like newly registered ones
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'member' FROM users;
+
+-- +goose Down
+DROP TABLE user_roles;
+DROP TABLE role_permissions;
+DROP TABLE permissions;
+DROP TABLE roles;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Lets administrators disable an account without deleting it. NULL means the account is active.
+
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMP WITH TIME ZONE;
+
+-- Speeds up the admin user list, which is ordered by creation time
+CREATE INDEX idx_users_created_at ON users(created_at);
+
+-- +goose Down
+DROP INDEX idx_users_created_at;
+ALTER TABLE users DROP COLUMN disabled_at;
@@ -0,0 +1,55 @@
+-- +goose Up
+-- Append-only log of authentication and account events.
+-- actor_id and subject_id have no foreign keys, so the history survives account deletion.
+
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+
+    -- e.g. 'auth.login', 'role.assigned' (see domain.AuditEvent*)
+    event_type TEXT NOT NULL,
+    -- 'success' or 'failure'
+    outcome TEXT NOT NULL,
+
+    -- Who performed the action (NULL for anonymous requests such as a failed login)
+    actor_id UUID,
+    -- The account the action applied to (NULL if unknown)
+    subject_id UUID,
+    -- Email given in the request; identifies the target when no account matched
+    email TEXT NOT NULL DEFAULT '',
+
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+
+    details JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_audit_events_occurred_at ON audit_events(occurred_at);
+CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id);
+CREATE INDEX idx_audit_events_subject_id ON audit_events(subject_id);
+
+-- Reject any change to existing rows, even from the application's own database user
+-- +goose StatementBegin
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER trg_audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+-- Reading the log is an admin-only permission
+INSERT INTO permissions (name, description) VALUES ('audit:read', 'View the audit log');
+INSERT INTO role_permissions (role, permission) VALUES ('admin', 'audit:read');
+
+-- +goose Down
+DELETE FROM permissions WHERE name = 'audit:read';
+DROP TABLE audit_events;
+DROP FUNCTION audit_events_append_only();
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Tamper evidence for the audit log: every row stores the SHA-256 of its contents chained to the
+-- hash of the previous row (see domain.AuditEvent.ComputeHash), so editing or deleting a row breaks
+-- every later link. Rows written before this migration keep NULL hashes and precede the chain.
+
+ALTER TABLE audit_events ADD COLUMN prev_hash TEXT;
+ALTER TABLE audit_events ADD COLUMN hash TEXT;
+
+-- A row can only have one successor, so the chain cannot fork
+CREATE UNIQUE INDEX idx_audit_events_prev_hash ON audit_events(prev_hash);
+
+-- +goose Down
+DROP INDEX idx_audit_events_prev_hash;
+ALTER TABLE audit_events DROP COLUMN hash;
+ALTER TABLE audit_events DROP COLUMN prev_hash;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Single-use tokens emailed for passwordless (magic link) login.
+
+CREATE TABLE magic_link_tokens (
+    -- Keyed hash of the token (see security.HashCode); the raw token is only sent by email
+    token_hash TEXT PRIMARY KEY,
+
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX idx_magic_link_tokens_user_id ON magic_link_tokens(user_id);
+
+-- +goose Down
+DROP TABLE magic_link_tokens;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Personal access tokens for scripts and CI (Authorization: Bearer mp_pat_...).
+
+CREATE TABLE personal_access_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    name TEXT NOT NULL,
+    -- Start of the token, shown in lists so users can tell their tokens apart
+    token_prefix TEXT NOT NULL,
+    -- SHA-256 of the token; the token itself is only shown once on creation
+    token_hash TEXT NOT NULL UNIQUE,
+    -- Permission names (see the permissions table) the token may use
+    scopes TEXT[] NOT NULL,
+
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);
+
+-- +goose Down
+DROP TABLE personal_access_tokens;
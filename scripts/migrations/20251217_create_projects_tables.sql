@@ -0,0 +1,35 @@
+-- +goose Up
+-- Projects and their members with a per-project role.
+
+CREATE TABLE projects (
+    id UUID PRIMARY KEY,
+    -- Short unique name such as 'MANPRO'; upper-case letters and digits, starting with a letter
+    key TEXT NOT NULL UNIQUE CHECK (key ~ '^[A-Z][A-Z0-9]{1,9}$'),
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    -- The API hands a project to another admin before deleting its owner's account;
+    -- SET NULL only keeps the project if a user row is deleted directly
+    owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    start_date DATE,
+    end_date DATE CHECK (end_date IS NULL OR start_date IS NULL OR end_date >= start_date),
+    status TEXT NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'on_hold', 'completed', 'cancelled')),
+    -- Archived projects are read-only and hidden from the default project list
+    archived_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE TABLE project_members (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('viewer', 'editor', 'admin')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (project_id, user_id)
+);
+
+-- Listing the projects of a user
+CREATE INDEX idx_project_members_user_id ON project_members(user_id);
+
+-- +goose Down
+DROP TABLE project_members;
+DROP TABLE projects;
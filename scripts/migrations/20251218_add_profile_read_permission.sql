@@ -0,0 +1,12 @@
+-- +goose Up
+-- Routes that accept Firebase ID tokens are authorized by permission like the other protected routes;
+-- every system role may read its own profile.
+INSERT INTO permissions (name, description) VALUES ('profile:read', 'View the signed-in profile');
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'profile:read'),
+    ('manager', 'profile:read'),
+    ('member', 'profile:read'),
+    ('guest', 'profile:read');
+
+-- +goose Down
+DELETE FROM permissions WHERE name = 'profile:read';